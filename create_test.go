@@ -1,6 +1,10 @@
 package zee
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/wyattis/zee/schema"
@@ -70,3 +74,119 @@ func TestSqliteUp(t *testing.T) {
 	}
 
 }
+
+func TestSqliteDown(t *testing.T) {
+	db, err := setupSqlite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	migrations := append(append([]Migration{}, userCommentMigrations...), Migration{
+		Version: 3,
+		Up: func(s *schema.Schema) {
+			s.Table("comment", func(t *schema.Table) {
+				t.Integer("likes").Default(0)
+			})
+			s.Create("tag", func(t *schema.Table) {
+				t.Primary("id")
+				t.String("name").Index("idx_tag_name")
+			})
+		},
+	})
+	if err := MigrateUpTo(migrations, db, 3, nil); err != nil {
+		t.Fatalf("Failed to migrate to version 3: %s", err)
+	}
+	if err := MigrateDownTo(migrations, db, 2, nil); err != nil {
+		t.Fatalf("Failed to roll back version 3 without a Down mutator: %s", err)
+	}
+	if _, err = db.Exec("SELECT likes FROM comment"); err == nil {
+		t.Error("Expected likes column to be dropped")
+	}
+	if _, err = db.Exec("SELECT * FROM tag"); err == nil {
+		t.Error("Expected tag table to be dropped")
+	}
+	mi := NewMigrator(db, MigrateOptions{})
+	mi.opts.Default()
+	mi.Add(migrations...)
+	if err := mi.To(0); err != nil {
+		t.Fatalf("Failed to roll back every migration: %s", err)
+	}
+	if err := mi.To(3); err != nil {
+		t.Fatalf("Failed to migrate back up to version 3: %s", err)
+	}
+}
+
+func TestSqliteVerify(t *testing.T) {
+	db, err := setupSqlite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	opts := MigrateOptions{}
+	opts.Default()
+	mi := NewMigrator(db, opts)
+	mi.Add(userCommentMigrations...)
+	if err := mi.UpTo(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := mi.Verify(); err != nil {
+		t.Fatalf("Expected unchanged migrations to verify: %s", err)
+	}
+
+	changed := append([]Migration{}, userCommentMigrations...)
+	changed[1].Up = func(s *schema.Schema) {
+		s.Create("comment", func(t *schema.Table) {
+			t.Primary("id")
+			t.Text("body")
+		})
+	}
+	mi = NewMigrator(db, opts)
+	mi.Add(changed...)
+	if err := mi.Verify(); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Expected a hash mismatch for a changed migration but got %v", err)
+	}
+}
+
+func TestSqliteVerifyLegacyHash(t *testing.T) {
+	db, err := setupSqlite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	opts := MigrateOptions{}
+	opts.Default()
+	mi := NewMigrator(db, opts)
+	mi.Add(userCommentMigrations...)
+	if err := mi.UpTo(2); err != nil {
+		t.Fatal(err)
+	}
+	// the MD5 recorded for the comment migration before hashes were versioned
+	legacy, _ := hex.DecodeString("32c1898d9f5bb00e5382123a1329e780")
+	q := fmt.Sprintf("UPDATE `%s` SET `hash` = ? WHERE `version` = 2", opts.MigrationTable)
+	if _, err := db.Exec(q, legacy); err != nil {
+		t.Fatal(err)
+	}
+	if err := mi.Verify(); !errors.Is(err, schema.ErrLegacyHash) {
+		t.Fatalf("Expected the legacy hash to be unverifiable but got %v", err)
+	}
+	if err := mi.Rehash(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mi.Verify(); err != nil {
+		t.Fatalf("Expected the rehashed migration to verify: %s", err)
+	}
+	var hash string
+	q = fmt.Sprintf("SELECT `hash` FROM `%s` WHERE `version` = 2", opts.MigrationTable)
+	if err := db.QueryRow(q).Scan(&hash); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "v1:") {
+		t.Errorf("Expected the legacy hash to be replaced with a versioned hash but got %q", hash)
+	}
+}
@@ -19,6 +19,7 @@ var (
 	ErrSchemaVersionLowerThanTarget  = fmt.Errorf("schema version is lower than target version")
 	ErrNoMigrationForVersion         = fmt.Errorf("no migration for version")
 	ErrDatabaseIsDirty               = fmt.Errorf("database is dirty")
+	ErrHashMismatch                  = fmt.Errorf("migration hash does not match")
 )
 
 var logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
@@ -74,6 +75,18 @@ func MigrateUpTo(migrations []Migration, db isql.IDB, version uint, opts *Migrat
 	return mi.UpTo(version)
 }
 
+// MigrateDownTo takes a list of migrations and rolls them back down to the provided version according to the provided
+// options.
+func MigrateDownTo(migrations []Migration, db isql.IDB, version uint, opts *MigrateOptions) (err error) {
+	if opts == nil {
+		opts = &MigrateOptions{}
+	}
+	opts.Default()
+	mi := NewMigrator(db, *opts)
+	mi.Add(migrations...)
+	return mi.DownTo(version)
+}
+
 // Migrate up to the provided version. Throws an errors if there isn't a migration matching the provided version, if the
 // schema version is higher than the provided version or if the database is dirty (failed a previous migration).
 func UpTo(db *sql.DB, driverType driver.Type, version uint, opts *MigrateOptions) (err error) {
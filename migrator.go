@@ -2,10 +2,10 @@ package zee
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"sort"
 
 	"github.com/wyattis/zee/isql"
 	"github.com/wyattis/zee/schema"
@@ -83,16 +83,182 @@ func (mi *Migrator) UpTo(version uint) (err error) {
 	return
 }
 
-// DownTo migrates the database down to the given version. It will fail if the target version is higher than the current
-// schema version.
+// DownTo migrates the database down to the given version by running the Down mutator of every migration above it,
+// newest first. Migrations without a Down mutator are rolled back by reversing their Up mutator. It will fail if the
+// target version is higher than the current schema version. Version 0 rolls back every migration.
 func (mi *Migrator) DownTo(version uint) (err error) {
-	err = errors.New("not implemented")
+	if version > 0 && !hasMatchingVersion(mi.migrations, version) {
+		err = ErrNoMigrationForVersion
+		return
+	}
+	if !databaseIsClean(mi.db, mi.opts) {
+		err = ErrDatabaseIsDirty
+		return
+	}
+	schemaVersion, err := currentVersion(mi.db, mi.opts.Driver, mi.opts)
+	if err != nil {
+		return
+	}
+	if schemaVersion < version {
+		err = ErrSchemaVersionLowerThanTarget
+		return
+	}
+	migrations := append([]Migration{}, mi.migrations...)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version > migrations[j].Version
+	})
+	for _, m := range migrations {
+		if m.Version > schemaVersion || m.Version <= version {
+			continue
+		}
+		err = isql.Begin(mi.db, func(tx *sql.Tx) (err error) {
+			s, err := mi.downSchema(m)
+			if err != nil {
+				return
+			}
+			// mark current migration as dirty before we start
+			q := fmt.Sprintf("UPDATE `%s` SET `dirty` = ? WHERE `version` = ? and `namespace` = ?", mi.opts.MigrationTable)
+			if _, err = tx.Exec(q, true, m.Version, mi.opts.Namespace); err != nil {
+				return
+			}
+			if err = s.Schema.Run(tx, mi.logger); err != nil {
+				return
+			}
+			q = fmt.Sprintf("DELETE FROM `%s` WHERE `version` = ? and `namespace` = ?", mi.opts.MigrationTable)
+			_, err = tx.Exec(q, m.Version, mi.opts.Namespace)
+			return
+		})
+		if err != nil {
+			return fmt.Errorf("rolling back version %d: %w", m.Version, err)
+		}
+	}
+	mi.logger.Info("Database was rolled back to version", "version", version)
+	return
+}
+
+// downSchema returns the schema that rolls back a migration. It is derived from Up when the migration has no Down.
+func (mi *Migrator) downSchema(m Migration) (s *schema.Schema, err error) {
+	if m.Down != nil {
+		s = schema.New(mi.opts.Driver, mi.opts.SchemaName)
+		m.Down(s)
+		return
+	}
+	up := schema.New(mi.opts.Driver, mi.opts.SchemaName)
+	m.Up(up)
+	return up.Schema.Reverse()
+}
+
+// Verify checks that the migrations applied to the database haven't changed since they were run by comparing their
+// hashes using the hash version recorded with each migration. Hashes made before hashes were versioned can't be
+// verified and return an error wrapping schema.ErrLegacyHash. See Rehash.
+func (mi *Migrator) Verify() (err error) {
+	versions, hashes, err := mi.appliedHashes()
+	if err != nil {
+		return
+	}
+	for _, version := range versions {
+		var s *schema.Schema
+		if s, err = mi.upSchema(version); err != nil {
+			return
+		}
+		ok, err := s.Schema.VerifyHash(hashes[version])
+		if err != nil {
+			return fmt.Errorf("verifying version %d: %w", version, err)
+		}
+		if !ok {
+			return fmt.Errorf("version %d: %w", version, ErrHashMismatch)
+		}
+	}
+	return
+}
+
+// Rehash replaces the hashes of applied migrations made by older hash versions with the current version. Hashes that
+// can still be verified are checked first, but legacy hashes can't be, so only rehash once the migrations are known to
+// be unchanged since they were applied.
+func (mi *Migrator) Rehash() (err error) {
+	versions, hashes, err := mi.appliedHashes()
+	if err != nil {
+		return
+	}
+	for _, version := range versions {
+		hash := hashes[version]
+		hashVersion, err := schema.ParseHashVersion(hash)
+		if err != nil {
+			return fmt.Errorf("rehashing version %d: %w", version, err)
+		}
+		if hashVersion >= schema.HashVersion {
+			continue
+		}
+		s, err := mi.upSchema(version)
+		if err != nil {
+			return err
+		}
+		if hashVersion > 0 {
+			ok, err := s.Schema.VerifyHash(hash)
+			if err != nil {
+				return fmt.Errorf("verifying version %d: %w", version, err)
+			}
+			if !ok {
+				return fmt.Errorf("version %d: %w", version, ErrHashMismatch)
+			}
+		}
+		if hash, err = s.Schema.Hash(); err != nil {
+			return err
+		}
+		q := fmt.Sprintf("UPDATE `%s` SET `hash` = ? WHERE `version` = ? and `namespace` = ?", mi.opts.MigrationTable)
+		if _, err = mi.db.Exec(q, hash, version, mi.opts.Namespace); err != nil {
+			return err
+		}
+	}
 	return
 }
 
+// appliedHashes returns the versions of the applied migrations in order and their hashes
+func (mi *Migrator) appliedHashes() (versions []uint, hashes map[uint][]byte, err error) {
+	if err = initializeSchema(mi.db, mi.opts.Driver, mi.opts); err != nil {
+		return
+	}
+	q := fmt.Sprintf("SELECT `version`, `hash` FROM `%s` WHERE `namespace` = ? ORDER BY `version`", mi.opts.MigrationTable)
+	rows, err := mi.db.Query(q, mi.opts.Namespace)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	hashes = map[uint][]byte{}
+	for rows.Next() {
+		var version uint
+		var hash []byte
+		if err = rows.Scan(&version, &hash); err != nil {
+			return
+		}
+		hashes[version] = hash
+		versions = append(versions, version)
+	}
+	err = rows.Err()
+	return
+}
+
+// upSchema builds the up schema of the migration with the given version
+func (mi *Migrator) upSchema(version uint) (s *schema.Schema, err error) {
+	for _, m := range mi.migrations {
+		if m.Version == version {
+			s = schema.New(mi.opts.Driver, mi.opts.SchemaName)
+			m.Up(s)
+			return
+		}
+	}
+	return nil, fmt.Errorf("version %d: %w", version, ErrNoMigrationForVersion)
+}
+
 // To migrates the database to the given version. It will run migrations either up or down depending on the relationship
 // between the current schema version and the target version.
 func (mi *Migrator) To(version uint) (err error) {
-	err = errors.New("not implemented")
-	return
+	schemaVersion, err := currentVersion(mi.db, mi.opts.Driver, mi.opts)
+	if err != nil {
+		return
+	}
+	if schemaVersion > version {
+		return mi.DownTo(version)
+	}
+	return mi.UpTo(version)
 }
@@ -0,0 +1,16 @@
+package schema
+
+type checkDef struct {
+	Table *TableDef
+	Name  string
+	Expr  string
+}
+
+type checkBuilder struct {
+	check *checkDef
+}
+
+func (c *checkBuilder) Name(name string) *checkBuilder {
+	c.check.Name = name
+	return c
+}
@@ -1,5 +1,11 @@
 package schema
 
+import (
+	"fmt"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
 type ColumnType uint
 
 const (
@@ -26,6 +32,8 @@ const (
 	TypeBinary
 	TypeVarBinary
 	TypeBlob
+	// TypeCustom columns use the type named by CustomType, like a Postgres domain, composite type or extension type
+	TypeCustom
 )
 
 type columnRef struct {
@@ -42,20 +50,87 @@ type columnDef struct {
 	Name            string
 	Kind            ColumnType
 	KindLen         int
+	Precision       int
+	Scale           int
 	IsUnique        bool
 	IsNull          bool
 	IsPrimary       bool
 	IsAutoincrement bool
 	Comment         string
 	EnumValues      []interface{}
+	EnumName        string
+	CustomType      string
+	Charset         string
+	Collations      map[driver.Type]string
 	ReferenceTo     *columnRef
 	DefaultVal      interface{}
+	Checks          []*checkDef
+	GeneratedAs     string
+	IsStored        bool
+	// IsModified changes the definition of an existing column instead of adding it
+	IsModified bool
 }
 
 func (c *columnDef) SoloPrimary() bool {
 	return c.IsPrimary && c.table.tableDef.NumPrimary() == 1
 }
 
+// IsEnum reports whether this column only accepts its enum values
+func (c *columnDef) IsEnum() bool {
+	return c.Kind == TypeEnum
+}
+
+// EnumTypeName is the name of the Postgres type holding the enum values. It defaults to <table>_<column>.
+func (c *columnDef) EnumTypeName() string {
+	if c.EnumName != "" {
+		return c.EnumName
+	}
+	return fmt.Sprintf("%s_%s", c.table.tableDef.Name, c.Name)
+}
+
+// Enum returns the Postgres type created for this column, or nil if the column uses an existing type
+func (c *columnDef) Enum() *EnumDef {
+	if c.Kind != TypeEnum || len(c.EnumValues) == 0 {
+		return nil
+	}
+	return &EnumDef{Schema: c.table.tableDef.Schema, Name: c.EnumTypeName(), Values: c.EnumValues}
+}
+
+func (c *columnDef) schema() *SchemaDef {
+	return c.table.tableDef.Schema
+}
+
+func (c *columnDef) Table() *TableDef {
+	return c.table.tableDef
+}
+
+// GetCollation is the collation of the column for the schema's driver. Columns without one use the default collation.
+func (c *columnDef) GetCollation() string {
+	return c.Collations[c.Table().Schema.Driver]
+}
+
+// ForeignName is the name of the foreign key constraint created for this column. It matches the name used by
+// Schema.DropForeign.
+func (c *columnDef) ForeignName() string {
+	return fmt.Sprintf("fk_%s_%s", c.table.tableDef.Name, c.Name)
+}
+
+// Foreign returns the foreign key created by References, or nil if the column doesn't reference another table
+func (c *columnDef) Foreign() *foreignDef {
+	if c.ReferenceTo == nil {
+		return nil
+	}
+	return &foreignDef{
+		Table:      c.table.tableDef,
+		Name:       c.ForeignName(),
+		Columns:    []string{c.Name},
+		RefTable:   c.ReferenceTo.Table,
+		RefColumns: []string{c.ReferenceTo.Column},
+		OnUpdate:   c.ReferenceTo.OnUpdate,
+		OnDelete:   c.ReferenceTo.OnDelete,
+	}
+}
+
 type columnBuilder struct {
 	Column *columnDef
 }
@@ -73,7 +148,8 @@ func (c *columnBuilder) Autoincrement() *columnBuilder {
 
 func (c *columnBuilder) OnUpdate(action FkAction) *columnBuilder {
 	if c.Column.ReferenceTo == nil {
-		panic("cannot set OnUpdate on column without reference")
+		c.Column.schema().addError(fmt.Errorf("cannot set OnUpdate on column %s without reference", c.Column.Name))
+		return c
 	}
 	c.Column.ReferenceTo.OnUpdate = action
 	return c
@@ -81,9 +157,10 @@ func (c *columnBuilder) OnUpdate(action FkAction) *columnBuilder {
 
 func (c *columnBuilder) OnDelete(action FkAction) *columnBuilder {
 	if c.Column.ReferenceTo == nil {
-		panic("cannot set OnUpdate on column without reference")
+		c.Column.schema().addError(fmt.Errorf("cannot set OnDelete on column %s without reference", c.Column.Name))
+		return c
 	}
-	c.Column.ReferenceTo.OnUpdate = action
+	c.Column.ReferenceTo.OnDelete = action
 	return c
 }
 
@@ -120,14 +197,85 @@ func (c *columnBuilder) Values(values ...interface{}) *columnBuilder {
 	return c.applyMods(Values(values...))
 }
 
+// EnumType sets the name of the Postgres enum type. Without values the column uses a type created by
+// Schema.CreateEnum. Columns in the same schema that share a name share the type, which is only created once.
+func (c *columnBuilder) EnumType(name string) *columnBuilder {
+	return c.applyMods(EnumType(name))
+}
+
+// CustomType uses a Postgres type by name, like one created by Schema.CreateDomain or Schema.CreateType or added by an
+// extension. Plain names are quoted like the names of the types created by the schema, anything else like text[] or
+// public.citext is used verbatim.
+func (c *columnBuilder) CustomType(name string) *columnBuilder {
+	return c.applyMods(CustomType(name))
+}
+
 func (c *columnBuilder) Default(value interface{}) *columnBuilder {
 	return c.applyMods(Default(value))
 }
 
+// Collate sets the collation used to compare values of a text column for a driver, like NOCASE on SQLite,
+// utf8mb4_unicode_ci on MySQL or a collation such as "C" or "und-x-icu" on Postgres. Collation names differ between
+// drivers, so drivers without a collation use their default.
+func (c *columnBuilder) Collate(driverType driver.Type, collation string) *columnBuilder {
+	return c.applyMods(Collate(driverType, collation))
+}
+
+// Charset sets the MySQL character set of a text column. It's ignored by other drivers.
+func (c *columnBuilder) Charset(charset string) *columnBuilder {
+	return c.applyMods(Charset(charset))
+}
+
 func (c *columnBuilder) Comment(comment string) *columnBuilder {
 	return c.applyMods(Comment(comment))
 }
 
+// Generated computes the column from the given expression. Generated columns are virtual unless Stored is used, except
+// on Postgres which only supports stored generated columns. SQLite requires 3.31 or newer and can't add stored
+// generated columns to an existing table.
+func (c *columnBuilder) Generated(expr string) *columnBuilder {
+	return c.applyMods(Generated(expr))
+}
+
+// Stored saves the value of a generated column when the row is written instead of computing it when read
+func (c *columnBuilder) Stored() *columnBuilder {
+	return c.applyMods(Stored())
+}
+
+// Modify changes an existing column to this definition instead of adding it, for example to change its collation.
+// MySQL redefines the whole column, Postgres changes its type, collation, nullability and default and SQLite rebuilds
+// the table with the new column definition. The enum type of a modified Postgres column has to exist already, so new
+// types are created with Schema.CreateEnum.
+func (c *columnBuilder) Modify() *columnBuilder {
+	return c.applyMods(Modify())
+}
+
+// Length sets the length of VARCHAR, BINARY, VARBINARY and BIT columns
+func (c *columnBuilder) Length(n int) *columnBuilder {
+	return c.applyMods(Length(n))
+}
+
+// Precision sets the total number of digits of DECIMAL and NUMERIC columns or the minimum precision in bits of FLOAT
+// columns
+func (c *columnBuilder) Precision(p int) *columnBuilder {
+	return c.applyMods(Precision(p))
+}
+
+// Scale sets the number of digits after the decimal point of DECIMAL and NUMERIC columns
+func (c *columnBuilder) Scale(s int) *columnBuilder {
+	return c.applyMods(Scale(s))
+}
+
+// Check adds a check constraint to the column
+func (c *columnBuilder) Check(expr string) *columnBuilder {
+	return c.applyMods(Check(expr))
+}
+
+// NamedCheck adds a named check constraint to the column so it can be dropped later with Table.DropCheck
+func (c *columnBuilder) NamedCheck(name, expr string) *columnBuilder {
+	return c.applyMods(NamedCheck(name, expr))
+}
+
 func (c *columnBuilder) Type(t ColumnType) *columnBuilder {
 	return c.applyMods(Type(t))
 }
@@ -217,12 +365,14 @@ func Type(t ColumnType) ColumnMod {
 func Binary() ColumnMod {
 	return func(c *columnDef) {
 		c.Kind = TypeBinary
+		c.KindLen = 0
 	}
 }
 
 func VarBinary() ColumnMod {
 	return func(c *columnDef) {
 		c.Kind = TypeVarBinary
+		c.KindLen = 255
 	}
 }
 
@@ -286,6 +436,42 @@ func Decimal() ColumnMod {
 	}
 }
 
+func Numeric() ColumnMod {
+	return func(c *columnDef) {
+		c.Kind = TypeNumeric
+	}
+}
+
+func Double() ColumnMod {
+	return func(c *columnDef) {
+		c.Kind = TypeDouble
+	}
+}
+
+func Bit() ColumnMod {
+	return func(c *columnDef) {
+		c.Kind = TypeBit
+	}
+}
+
+func Length(n int) ColumnMod {
+	return func(c *columnDef) {
+		c.KindLen = n
+	}
+}
+
+func Precision(p int) ColumnMod {
+	return func(c *columnDef) {
+		c.Precision = p
+	}
+}
+
+func Scale(s int) ColumnMod {
+	return func(c *columnDef) {
+		c.Scale = s
+	}
+}
+
 func Enum(values ...interface{}) ColumnMod {
 	return func(c *columnDef) {
 		c.Kind = TypeEnum
@@ -299,6 +485,35 @@ func Values(values ...interface{}) ColumnMod {
 	}
 }
 
+func EnumType(name string) ColumnMod {
+	return func(c *columnDef) {
+		c.Kind = TypeEnum
+		c.EnumName = name
+	}
+}
+
+func Collate(driverType driver.Type, collation string) ColumnMod {
+	return func(c *columnDef) {
+		if c.Collations == nil {
+			c.Collations = map[driver.Type]string{}
+		}
+		c.Collations[driverType] = collation
+	}
+}
+
+func Charset(charset string) ColumnMod {
+	return func(c *columnDef) {
+		c.Charset = charset
+	}
+}
+
+func CustomType(name string) ColumnMod {
+	return func(c *columnDef) {
+		c.Kind = TypeCustom
+		c.CustomType = name
+	}
+}
+
 func References(table, col string) ColumnMod {
 	return func(c *columnDef) {
 		c.ReferenceTo = &columnRef{
@@ -313,3 +528,35 @@ func Default(value interface{}) ColumnMod {
 		c.DefaultVal = value
 	}
 }
+
+func Check(expr string) ColumnMod {
+	return NamedCheck("", expr)
+}
+
+func NamedCheck(name, expr string) ColumnMod {
+	return func(c *columnDef) {
+		c.Checks = append(c.Checks, &checkDef{
+			Table: c.table.tableDef,
+			Name:  name,
+			Expr:  expr,
+		})
+	}
+}
+
+func Generated(expr string) ColumnMod {
+	return func(c *columnDef) {
+		c.GeneratedAs = expr
+	}
+}
+
+func Stored() ColumnMod {
+	return func(c *columnDef) {
+		c.IsStored = true
+	}
+}
+
+func Modify() ColumnMod {
+	return func(c *columnDef) {
+		c.IsModified = true
+	}
+}
@@ -10,12 +10,15 @@ type Constant interface {
 type NOW struct{}
 
 func (n NOW) Constant(driverType driver.Type) string {
-	switch driverType {
-	case driver.TypeSqlite3:
-		return "CURRENT_TIMESTAMP"
-	default:
-		panic("unsupported driver type")
-	}
+	return "CURRENT_TIMESTAMP"
 }
 
 type CURRENT_TIMESTAMP = NOW
+
+// Expr is a default computed by an SQL expression like gen_random_uuid(). It is inserted without escaping so it must
+// never contain user input. MySQL requires 8.0.13 or newer for expression defaults.
+type Expr string
+
+func (e Expr) Constant(driverType driver.Type) string {
+	return "(" + string(e) + ")"
+}
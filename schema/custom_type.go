@@ -0,0 +1,73 @@
+package schema
+
+// ExtensionDef is a Postgres extension, like pgcrypto or citext
+type ExtensionDef struct {
+	Schema *SchemaDef
+	Name   string
+}
+
+type typeField struct {
+	Name string
+	Type string
+}
+
+// TypeDef is a Postgres composite type
+type TypeDef struct {
+	Schema *SchemaDef
+	Name   string
+	Fields []typeField
+}
+
+type TypeMutator func(t *CompositeType)
+
+type CompositeType struct {
+	typeDef *TypeDef
+}
+
+// Field adds a field to the type. The type of the field is rendered as is.
+func (t *CompositeType) Field(name, sqlType string) *CompositeType {
+	t.typeDef.Fields = append(t.typeDef.Fields, typeField{Name: name, Type: sqlType})
+	return t
+}
+
+// DomainDef is a Postgres domain, which is an existing type with constraints on its values
+type DomainDef struct {
+	Schema     *SchemaDef
+	Name       string
+	Type       string
+	IsNotNull  bool
+	DefaultVal interface{}
+	Checks     []*checkDef
+}
+
+// Default renders the default value of the domain
+func (d *DomainDef) Default() (string, error) {
+	return defaultValue(d.Schema.Driver, TypeCustom, d.DefaultVal)
+}
+
+type DomainMutator func(d *Domain)
+
+type Domain struct {
+	domainDef *DomainDef
+}
+
+func (d *Domain) NotNull() *Domain {
+	d.domainDef.IsNotNull = true
+	return d
+}
+
+func (d *Domain) Default(value interface{}) *Domain {
+	d.domainDef.DefaultVal = value
+	return d
+}
+
+// Check adds a constraint on the values of the domain. The value being checked is named VALUE in the expression.
+func (d *Domain) Check(expr string) *Domain {
+	d.domainDef.Checks = append(d.domainDef.Checks, &checkDef{Expr: expr})
+	return d
+}
+
+func (d *Domain) NamedCheck(name, expr string) *Domain {
+	d.domainDef.Checks = append(d.domainDef.Checks, &checkDef{Name: name, Expr: expr})
+	return d
+}
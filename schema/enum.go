@@ -0,0 +1,62 @@
+package schema
+
+// EnumDef is a Postgres enum type. Enum columns on other drivers don't need a separate type.
+type EnumDef struct {
+	Schema *SchemaDef
+	Name   string
+	Values []interface{}
+}
+
+// enumsBefore returns the names of the enum types that exist when the operation for table t runs
+func (s *SchemaDef) enumsBefore(t *TableDef) map[string]bool {
+	created := map[string]bool{}
+	for _, op := range s.Operations {
+		if op.Table == t {
+			break
+		}
+		switch op.Kind {
+		case OpCreateEnum:
+			created[op.Enum.Name] = true
+		case OpDropEnum:
+			delete(created, op.Enum.Name)
+		case OpCreateTable, OpAlterTable:
+			for _, e := range op.Table.columnEnums() {
+				created[e.Name] = true
+			}
+		case OpDropCreated:
+			created = map[string]bool{}
+		}
+	}
+	return created
+}
+
+func (e *EnumDef) createStatement() (string, error) {
+	tmp, err := e.Schema.loadTemplates()
+	if err != nil {
+		return "", err
+	}
+	return execTemplate(tmp, "create_enum", e)
+}
+
+func (e *EnumDef) addValueStatements() (statements []string, err error) {
+	tmp, err := e.Schema.loadTemplates()
+	if err != nil {
+		return
+	}
+	for _, v := range e.Values {
+		var sql string
+		if sql, err = execTemplate(tmp, "add_enum_value", &EnumDef{Schema: e.Schema, Name: e.Name, Values: []interface{}{v}}); err != nil {
+			return
+		}
+		statements = append(statements, sql)
+	}
+	return
+}
+
+func (e *EnumDef) dropStatement() (string, error) {
+	tmp, err := e.Schema.loadTemplates()
+	if err != nil {
+		return "", err
+	}
+	return execTemplate(tmp, "drop_enum", e)
+}
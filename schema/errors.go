@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+// ErrUnsupported is wrapped by errors for features the schema's driver doesn't support
+var ErrUnsupported = errors.New("not supported")
+
+func unsupported(feature string, driverType driver.Type) error {
+	return fmt.Errorf("%s are %w by %s", feature, ErrUnsupported, driverType)
+}
+
+// ErrIrreversible is returned when reversing a schema that drops something or runs raw statements
+var ErrIrreversible = errors.New("irreversible operation")
+
+func irreversible(format string, args ...interface{}) error {
+	return fmt.Errorf("can't reverse %s: %w", fmt.Sprintf(format, args...), ErrIrreversible)
+}
+
+// ErrForeignKeysEnabled is returned when a SQLite table has to be rebuilt while foreign key enforcement is on.
+// Dropping the old table would run the ON DELETE actions of the tables referencing it.
+var ErrForeignKeysEnabled = errors.New("foreign_keys is enabled")
+
+// ErrNoFTS5 is returned when a SQLite full-text search is created without the FTS5 module. github.com/mattn/go-sqlite3
+// only includes it when built with the sqlite_fts5 tag.
+var ErrNoFTS5 = errors.New("SQLite FTS5 module is not available, build with -tags sqlite_fts5")
+
+// ErrLegacyHash is returned when verifying an unversioned MD5 hash. They were made from SQL rendered by older templates
+// so they can't be checked against the schema.
+var ErrLegacyHash = errors.New("legacy hash can't be verified")
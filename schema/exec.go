@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+// ExecDef is a raw statement run with bound parameters. Statements written for a single driver, like ones using
+// Postgres $1 placeholders, go in Variants.
+type ExecDef struct {
+	Schema   *SchemaDef
+	Sql      string
+	Variants map[driver.Type]string
+	Params   []interface{}
+}
+
+// Statement returns the statement for the schema's driver
+func (e *ExecDef) Statement() (Statement, error) {
+	if sql, ok := e.Variants[e.Schema.Driver]; ok {
+		return Statement{Sql: sql, Params: e.Params}, nil
+	}
+	if e.Sql == "" {
+		return Statement{}, fmt.Errorf("exec has no statement for %s", e.Schema.Driver)
+	}
+	return Statement{Sql: e.Sql, Params: e.Params}, nil
+}
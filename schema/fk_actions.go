@@ -9,54 +9,29 @@ type FkAction interface {
 type NO_ACTION struct{}
 
 func (n NO_ACTION) Action(driverType driver.Type) string {
-	switch driverType {
-	case driver.TypeSqlite3:
-		return "NO ACTION"
-	default:
-		panic("unknown driver type")
-	}
+	return "NO ACTION"
 }
 
 type RESTRICT struct{}
 
 func (n RESTRICT) Action(driverType driver.Type) string {
-	switch driverType {
-	case driver.TypeSqlite3:
-		return "RESTRICT"
-	default:
-		panic("unknown driver type")
-	}
+	return "RESTRICT"
 }
 
 type SET_NULL struct{}
 
 func (n SET_NULL) Action(driverType driver.Type) string {
-	switch driverType {
-	case driver.TypeSqlite3:
-		return "SET NULL"
-	default:
-		panic("unknown driver type")
-	}
+	return "SET NULL"
 }
 
 type SET_DEFAULT struct{}
 
 func (n SET_DEFAULT) Action(driverType driver.Type) string {
-	switch driverType {
-	case driver.TypeSqlite3:
-		return "SET DEFAULT"
-	default:
-		panic("unknown driver type")
-	}
+	return "SET DEFAULT"
 }
 
 type CASCADE struct{}
 
 func (n CASCADE) Action(driverType driver.Type) string {
-	switch driverType {
-	case driver.TypeSqlite3:
-		return "CASCADE"
-	default:
-		panic("unknown driver type")
-	}
+	return "CASCADE"
 }
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type foreignDef struct {
+	Table      *TableDef
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   FkAction
+	OnDelete   FkAction
+}
+
+// GetName returns the constraint name, defaulting to fk_<table>_<columns> like column references do
+func (f *foreignDef) GetName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return fmt.Sprintf("fk_%s_%s", f.Table.Name, strings.Join(f.Columns, "_"))
+}
+
+type foreignBuilder struct {
+	foreign *foreignDef
+}
+
+// References sets the referenced table and columns. Columns are matched to the local columns by position.
+func (f *foreignBuilder) References(table string, cols ...string) *foreignBuilder {
+	f.foreign.RefTable = table
+	f.foreign.RefColumns = cols
+	return f
+}
+
+func (f *foreignBuilder) Name(name string) *foreignBuilder {
+	f.foreign.Name = name
+	return f
+}
+
+func (f *foreignBuilder) OnUpdate(action FkAction) *foreignBuilder {
+	f.foreign.OnUpdate = action
+	return f
+}
+
+func (f *foreignBuilder) OnDelete(action FkAction) *foreignBuilder {
+	f.foreign.OnDelete = action
+	return f
+}
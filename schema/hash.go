@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// HashVersion is the version of the algorithm used by Hash. It is recorded in every hash so hashes made by older
+// versions of zee can still be verified with the algorithm that made them.
+const HashVersion = 1
+
+// hashers computes the digest of a schema by hash version
+var hashers = map[int]func(s *SchemaDef) ([]byte, error){
+	1: operationHash,
+}
+
+var hashVersionPattern = regexp.MustCompile(`^v(\d+):`)
+
+// ParseHashVersion returns the version of the algorithm that made hash. Unversioned MD5 hashes made before hashes were
+// versioned are version 0.
+func ParseHashVersion(hash []byte) (version int, err error) {
+	if match := hashVersionPattern.FindSubmatch(hash); match != nil {
+		return strconv.Atoi(string(match[1]))
+	}
+	if len(hash) == md5.Size {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unrecognized hash %q", hash)
+}
+
+// Hash returns a versioned checksum of the operations in the schema
+func (s *SchemaDef) Hash() (hash []byte, err error) {
+	return s.versionedHash(HashVersion)
+}
+
+func (s *SchemaDef) versionedHash(version int) (hash []byte, err error) {
+	hasher, ok := hashers[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash version %d", version)
+	}
+	sum, err := hasher(s)
+	if err != nil {
+		return
+	}
+	return []byte(fmt.Sprintf("v%d:%s", version, hex.EncodeToString(sum))), nil
+}
+
+// VerifyHash reports whether hash matches the schema using the hash version recorded in it. Legacy MD5 hashes were
+// made from SQL rendered by older templates so they can't be recomputed and return ErrLegacyHash.
+func (s *SchemaDef) VerifyHash(hash []byte) (ok bool, err error) {
+	version, err := ParseHashVersion(hash)
+	if err != nil {
+		return
+	}
+	if version == 0 {
+		return false, ErrLegacyHash
+	}
+	expected, err := s.versionedHash(version)
+	if err != nil {
+		return
+	}
+	return string(expected) == string(hash), nil
+}
+
+// operationHash is a SHA-256 over the operations in the schema instead of the SQL they render, so changes to the
+// templates don't change the hash. Only exported fields that are set are hashed so adding an option doesn't change the
+// hash of schemas that don't use it.
+func operationHash(s *SchemaDef) (hash []byte, err error) {
+	if err = s.validate(); err != nil {
+		return
+	}
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%s\n", s.Driver)
+	for _, op := range s.Operations {
+		hashValue(sum, reflect.ValueOf(op))
+		fmt.Fprintln(sum)
+	}
+	return sum.Sum(nil), nil
+}
+
+var (
+	operationType = reflect.TypeOf(Operation{})
+	schemaDefType = reflect.TypeOf(&SchemaDef{})
+	tableDefType  = reflect.TypeOf(&TableDef{})
+)
+
+func hashValue(w hash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			hashValue(w, v.Elem())
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			fmt.Fprintf(w, "%s(", v.Elem().Type())
+			hashValue(w, v.Elem())
+			fmt.Fprint(w, ")")
+		}
+	case reflect.Struct:
+		t := v.Type()
+		fmt.Fprintf(w, "%s{", t.Name())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			// definitions point back at their schema and table
+			if !field.IsExported() || field.Type == schemaDefType || (field.Type == tableDefType && t != operationType) {
+				continue
+			}
+			if value := v.Field(i); !value.IsZero() {
+				fmt.Fprintf(w, "%s:", field.Name)
+				hashValue(w, value)
+				fmt.Fprint(w, ",")
+			}
+		}
+		fmt.Fprint(w, "}")
+	case reflect.Slice, reflect.Array:
+		fmt.Fprint(w, "[")
+		for i := 0; i < v.Len(); i++ {
+			hashValue(w, v.Index(i))
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprint(w, "]")
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		fmt.Fprint(w, "map[")
+		for _, key := range keys {
+			hashValue(w, key)
+			fmt.Fprint(w, ":")
+			hashValue(w, v.MapIndex(key))
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprint(w, "]")
+	case reflect.String:
+		fmt.Fprintf(w, "%q", v.String())
+	case reflect.Func, reflect.Chan:
+	default:
+		fmt.Fprintf(w, "%v", v)
+	}
+}
@@ -1,11 +1,94 @@
 package schema
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+type IndexMethod string
+
+const (
+	IndexBtree  IndexMethod = "btree"
+	IndexHash   IndexMethod = "hash"
+	IndexGin    IndexMethod = "gin"
+	IndexGist   IndexMethod = "gist"
+	IndexBrin   IndexMethod = "brin"
+	IndexSpgist IndexMethod = "spgist"
+)
+
+var indexMethods = map[driver.Type][]IndexMethod{
+	driver.TypeMysql:    {IndexBtree, IndexHash},
+	driver.TypePostgres: {IndexBtree, IndexHash, IndexGin, IndexGist, IndexBrin, IndexSpgist},
+}
+
+type indexColumn struct {
+	Name    string
+	Expr    string
+	Order   string
+	Collate string
+	// Length indexes a prefix of the column on MySQL
+	Length int
+}
+
+type indexParam struct {
+	Name  string
+	Value interface{}
+}
+
 type indexDef struct {
 	Table       *TableDef
 	Name        string
 	Unique      bool
 	IfNotExists bool
-	Columns     []string
+	Columns     []*indexColumn
+	Where       string
+	Method      IndexMethod
+	Include     []string
+	Fulltext    bool
+	Spatial     bool
+	Params      []indexParam
+}
+
+func newIndexColumns(cols []string) (res []*indexColumn) {
+	for _, col := range cols {
+		res = append(res, &indexColumn{Name: col})
+	}
+	return
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// GetName returns the name of the index, defaulting to unq_<table>_<columns>. Expressions are included in the default
+// name with anything that isn't valid in an identifier replaced by underscores.
+func (i *indexDef) GetName() string {
+	if i.Name != "" {
+		return i.Name
+	}
+	parts := []string{}
+	for _, col := range i.Columns {
+		if col.Expr != "" {
+			parts = append(parts, strings.Trim(nonIdentifierChars.ReplaceAllString(col.Expr, "_"), "_"))
+		} else {
+			parts = append(parts, col.Name)
+		}
+	}
+	return fmt.Sprintf("unq_%s_%s", i.Table.Name, strings.Join(parts, "_"))
+}
+
+// Using returns the index method after checking that the schema's driver supports it
+func (i *indexDef) Using() (method IndexMethod, err error) {
+	if i.Method == "" {
+		return
+	}
+	for _, m := range indexMethods[i.Table.Schema.Driver] {
+		if m == i.Method {
+			return i.Method, nil
+		}
+	}
+	return "", fmt.Errorf("index method %s is %w by %s", i.Method, ErrUnsupported, i.Table.Schema.Driver)
 }
 
 type indexBuilder struct {
@@ -26,3 +109,91 @@ func (t *indexBuilder) IfNotExists() *indexBuilder {
 	t.index.IfNotExists = true
 	return t
 }
+
+// Expr adds an expression like lower(email) to the index. MySQL requires 8.0.13 or newer for expressions.
+func (t *indexBuilder) Expr(expr string) *indexBuilder {
+	t.index.Columns = append(t.index.Columns, &indexColumn{Expr: expr})
+	return t
+}
+
+// Where makes this a partial index only containing rows that match the predicate. MySQL doesn't support partial
+// indices.
+func (t *indexBuilder) Where(predicate string) *indexBuilder {
+	t.index.Where = predicate
+	return t
+}
+
+// Asc sorts the given columns or expressions in ascending order
+func (t *indexBuilder) Asc(cols ...string) *indexBuilder {
+	return t.order("ASC", cols)
+}
+
+// Desc sorts the given columns or expressions in descending order
+func (t *indexBuilder) Desc(cols ...string) *indexBuilder {
+	return t.order("DESC", cols)
+}
+
+// Collate sets the collation used for a column or expression. MySQL doesn't support per column collation in indices.
+func (t *indexBuilder) Collate(col string, collation string) *indexBuilder {
+	if c := t.index.find(col); c != nil {
+		c.Collate = collation
+	}
+	return t
+}
+
+func (t *indexBuilder) order(order string, cols []string) *indexBuilder {
+	for _, col := range cols {
+		if c := t.index.find(col); c != nil {
+			c.Order = order
+		}
+	}
+	return t
+}
+
+func (i *indexDef) find(col string) *indexColumn {
+	for _, c := range i.Columns {
+		if c.Name == col || (c.Expr != "" && c.Expr == col) {
+			return c
+		}
+	}
+	i.Table.Schema.addError(fmt.Errorf("index %s has no column %s", i.GetName(), col))
+	return nil
+}
+
+// Using sets the index method, like gin for Postgres JSONB columns. SQLite doesn't support index methods.
+func (t *indexBuilder) Using(method IndexMethod) *indexBuilder {
+	t.index.Method = method
+	return t
+}
+
+// Include adds non-key columns to a Postgres covering index
+func (t *indexBuilder) Include(cols ...string) *indexBuilder {
+	t.index.Include = append(t.index.Include, cols...)
+	return t
+}
+
+// Prefix only indexes the first n characters of a column on MySQL, which is required for TEXT and BLOB columns
+func (t *indexBuilder) Prefix(col string, n int) *indexBuilder {
+	if c := t.index.find(col); c != nil {
+		c.Length = n
+	}
+	return t
+}
+
+// Fulltext creates a MySQL FULLTEXT index
+func (t *indexBuilder) Fulltext() *indexBuilder {
+	t.index.Fulltext = true
+	return t
+}
+
+// Spatial creates a MySQL SPATIAL index
+func (t *indexBuilder) Spatial() *indexBuilder {
+	t.index.Spatial = true
+	return t
+}
+
+// With sets a Postgres storage parameter, like fillfactor
+func (t *indexBuilder) With(param string, value interface{}) *indexBuilder {
+	t.index.Params = append(t.index.Params, indexParam{Name: param, Value: value})
+	return t
+}
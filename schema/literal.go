@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+// quoteString returns s as a string literal for the given driver
+func quoteString(driverType driver.Type, s string) string {
+	if driverType == driver.TypeMysql {
+		// backslashes are escape characters in MySQL strings unless NO_BACKSLASH_ESCAPES is enabled
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteStrings formats each value as a string literal and joins them with commas
+func quoteStrings(driverType driver.Type, values []interface{}) string {
+	res := make([]string, len(values))
+	for i, v := range values {
+		res[i] = quoteString(driverType, fmt.Sprint(v))
+	}
+	return strings.Join(res, ", ")
+}
+
+// defaultValue renders val as the default of a column with the given kind. Values are always rendered as literals
+// unless they implement Constant, which is how Expr defaults are inserted without escaping.
+func defaultValue(driverType driver.Type, kind ColumnType, val interface{}) (res string, err error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case Constant:
+		return v.Constant(driverType), nil
+	case string, []byte:
+	default:
+		// anything other than a string is encoded for JSON columns
+		if kind == TypeJson {
+			var b []byte
+			if b, err = json.Marshal(val); err != nil {
+				return
+			}
+			val = string(b)
+		}
+	}
+	switch v := val.(type) {
+	case []byte:
+		if driverType == driver.TypePostgres {
+			res = fmt.Sprintf(`'\x%x'`, v)
+		} else {
+			res = fmt.Sprintf("X'%x'", v)
+		}
+	case time.Time:
+		switch kind {
+		case TypeDate:
+			res = quoteString(driverType, v.Format("2006-01-02"))
+		case TypeTime:
+			res = quoteString(driverType, v.Format("15:04:05"))
+		default:
+			res = quoteString(driverType, v.Format("2006-01-02 15:04:05"))
+		}
+	case string:
+		if kind == TypeBoolean {
+			return boolDefault(val)
+		}
+		res = quoteString(driverType, v)
+	case bool:
+		return boolDefault(val)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		if kind == TypeBoolean {
+			return boolDefault(val)
+		}
+		res = fmt.Sprint(v)
+	default:
+		return "", fmt.Errorf("unsupported default value %v of type %T", val, val)
+	}
+	// MySQL only allows expression defaults on TEXT, BLOB and JSON columns
+	if driverType == driver.TypeMysql {
+		switch kind {
+		case TypeText, TypeJson, TypeBlob:
+			res = "(" + res + ")"
+		}
+	}
+	return
+}
+
+func boolDefault(val interface{}) (string, error) {
+	b, err := strconv.ParseBool(fmt.Sprint(val))
+	if err != nil {
+		return "", fmt.Errorf("invalid boolean default %v", val)
+	}
+	if b {
+		return "TRUE", nil
+	}
+	return "FALSE", nil
+}
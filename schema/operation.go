@@ -0,0 +1,72 @@
+package schema
+
+type OpKind string
+
+const (
+	OpCreateTable     OpKind = "create_table"
+	OpAlterTable      OpKind = "alter_table"
+	OpDropTable       OpKind = "drop_table"
+	OpDropIndex       OpKind = "drop_index"
+	OpCreateView      OpKind = "create_view"
+	OpRefreshView     OpKind = "refresh_view"
+	OpDropView        OpKind = "drop_view"
+	OpCreateTrigger   OpKind = "create_trigger"
+	OpDropTrigger     OpKind = "drop_trigger"
+	OpCreateEnum      OpKind = "create_enum"
+	OpAddEnumValue    OpKind = "add_enum_value"
+	OpDropEnum        OpKind = "drop_enum"
+	OpCreateSequence  OpKind = "create_sequence"
+	OpRestartSequence OpKind = "restart_sequence"
+	OpDropSequence    OpKind = "drop_sequence"
+	OpCreateExtension OpKind = "create_extension"
+	OpDropExtension   OpKind = "drop_extension"
+	OpCreateType      OpKind = "create_type"
+	OpDropType        OpKind = "drop_type"
+	OpCreateDomain    OpKind = "create_domain"
+	OpDropDomain      OpKind = "drop_domain"
+	OpCreatePartition OpKind = "create_partition"
+	OpAttachPartition OpKind = "attach_partition"
+	OpDetachPartition OpKind = "detach_partition"
+	OpDropPartition   OpKind = "drop_partition"
+	OpGrant           OpKind = "grant"
+	OpRevoke          OpKind = "revoke"
+	OpExec            OpKind = "exec"
+	OpDropCreated     OpKind = "drop_created"
+)
+
+// Operation is a single call made on a Schema. Operations are rendered in the order they were made, so they can be
+// inspected, reordered or replaced before the schema runs.
+type Operation struct {
+	Kind OpKind
+	// Table is set for OpCreateTable and OpAlterTable
+	Table *TableDef
+	// View is set for OpCreateView, OpRefreshView and OpDropView
+	View *ViewDef
+	// Trigger is set for OpCreateTrigger and OpDropTrigger
+	Trigger *TriggerDef
+	// Enum is set for OpCreateEnum, OpAddEnumValue and OpDropEnum
+	Enum *EnumDef
+	// Sequence is set for OpCreateSequence, OpRestartSequence and OpDropSequence
+	Sequence *SequenceDef
+	// Extension is set for OpCreateExtension and OpDropExtension
+	Extension *ExtensionDef
+	// Type is set for OpCreateType and OpDropType
+	Type *TypeDef
+	// Domain is set for OpCreateDomain and OpDropDomain
+	Domain *DomainDef
+	// Partition is set for OpCreatePartition, OpAttachPartition, OpDetachPartition and OpDropPartition
+	Partition *PartitionDef
+	// Grant is set for OpGrant and OpRevoke
+	Grant *GrantDef
+	// Name is the table or index dropped by OpDropTable and OpDropIndex
+	Name string
+	// Exec is the statement run by OpExec
+	Exec *ExecDef
+}
+
+// grouped reports whether consecutive operations of this kind are rendered together. Tables created or altered
+// together are ordered by their references and tables dropped together are dropped by a single statement.
+func (k OpKind) grouped(next OpKind) bool {
+	isTable := func(k OpKind) bool { return k == OpCreateTable || k == OpAlterTable }
+	return (isTable(k) && isTable(next)) || (k == OpDropTable && next == OpDropTable)
+}
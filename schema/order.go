@@ -0,0 +1,49 @@
+package schema
+
+import "github.com/wyattis/zee/isql/driver"
+
+// orderTables sorts the tables so that created tables come before the tables referencing them. The order the tables
+// were declared in is kept otherwise. Foreign keys that would form a cycle are deferred on MySQL and
+// Postgres and added once every table exists. SQLite doesn't check references when creating tables, so it never
+// defers foreign keys.
+func (s *SchemaDef) orderTables(tables []*TableDef) (ordered []*TableDef, deferred []*foreignDef) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	created := map[string]*TableDef{}
+	for _, t := range tables {
+		if t.WillCreate {
+			created[t.Name] = t
+		}
+	}
+	state := map[*TableDef]int{}
+	var visit func(t *TableDef)
+	visit = func(t *TableDef) {
+		state[t] = visiting
+		t.deferredForeigns = nil
+		for _, f := range t.Foreigns() {
+			ref := created[f.RefTable]
+			if ref == nil || ref == t {
+				continue
+			}
+			switch state[ref] {
+			case visiting:
+				if s.Driver != driver.TypeSqlite3 {
+					t.deferredForeigns = append(t.deferredForeigns, f.GetName())
+					deferred = append(deferred, f)
+				}
+			case 0:
+				visit(ref)
+			}
+		}
+		state[t] = visited
+		ordered = append(ordered, t)
+	}
+	for _, t := range tables {
+		if state[t] == 0 {
+			visit(t)
+		}
+	}
+	return
+}
@@ -0,0 +1,141 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+type PartitionMethod string
+
+const (
+	PartitionRange PartitionMethod = "RANGE"
+	PartitionList  PartitionMethod = "LIST"
+	PartitionHash  PartitionMethod = "HASH"
+)
+
+// partitioningDef splits the rows of a table into partitions by the value of its columns
+type partitioningDef struct {
+	Method  PartitionMethod
+	Columns []string
+}
+
+// PartitionDef is a single partition of a partitioned table. Postgres partitions are tables of their own while MySQL
+// partitions are part of their table.
+type PartitionDef struct {
+	Schema *SchemaDef
+	// Table is the partitioned table
+	Table string
+	Name  string
+	// From and To bound range partitions. MySQL only uses To. Without a bound the range is unbounded on that side.
+	From interface{}
+	To   interface{}
+	// In are the values of a list partition
+	In []interface{}
+	// Modulus and Remainder select the rows of a Postgres hash partition
+	Modulus   int
+	Remainder int
+	IsDefault bool
+}
+
+// FromValue is the lower bound of a range partition
+func (p *PartitionDef) FromValue() (string, error) {
+	if p.From == nil {
+		return "MINVALUE", nil
+	}
+	return defaultValue(p.Schema.Driver, TypeVarChar, p.From)
+}
+
+// ToValue is the upper bound of a range partition
+func (p *PartitionDef) ToValue() (string, error) {
+	if p.To == nil {
+		return "MAXVALUE", nil
+	}
+	return defaultValue(p.Schema.Driver, TypeVarChar, p.To)
+}
+
+// InValues are the values of a list partition
+func (p *PartitionDef) InValues() (string, error) {
+	res := make([]string, len(p.In))
+	for i, v := range p.In {
+		var err error
+		if res[i], err = defaultValue(p.Schema.Driver, TypeVarChar, v); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(res, ", "), nil
+}
+
+// validate checks that the partition has exactly one bound and that it fits the partitioning method. The method is
+// empty when the partitioned table isn't part of the schema.
+func (p *PartitionDef) validate(method PartitionMethod) (errs []error) {
+	hash := p.Modulus != 0 || p.Remainder != 0
+	methods := []PartitionMethod{PartitionRange, PartitionList, PartitionHash}
+	bounds := []bool{p.From != nil || p.To != nil, len(p.In) > 0, hash}
+	count := 0
+	for _, set := range bounds {
+		if set {
+			count++
+		}
+	}
+	if p.IsDefault {
+		count++
+	}
+	switch {
+	case count > 1:
+		errs = append(errs, fmt.Errorf("partition %s has more than one bound", p.Name))
+	// MySQL assigns rows to hash partitions itself, so they may not have a bound
+	case count == 0 && !(p.Schema.Driver == driver.TypeMysql && (method == PartitionHash || method == "")):
+		errs = append(errs, fmt.Errorf("partition %s has no bound", p.Name))
+	}
+	if method != "" {
+		for i, set := range bounds {
+			if set && methods[i] != method {
+				errs = append(errs, fmt.Errorf("partition %s has a %s bound but %s is partitioned by %s", p.Name, methods[i], p.Table, method))
+			}
+		}
+	}
+	if p.IsDefault && (method == PartitionHash || (method == PartitionList && p.Schema.Driver == driver.TypeMysql)) {
+		errs = append(errs, fmt.Errorf("default partitions of %s partitioned tables are %w by %s", method, ErrUnsupported, p.Schema.Driver))
+	}
+	if hash && p.Schema.Driver == driver.TypePostgres && (p.Modulus <= 0 || p.Remainder < 0 || p.Remainder >= p.Modulus) {
+		errs = append(errs, fmt.Errorf("partition %s needs a positive modulus and a remainder less than it", p.Name))
+	}
+	return
+}
+
+type PartitionMutator func(p *Partition)
+
+type Partition struct {
+	partitionDef *PartitionDef
+}
+
+// Range holds rows with values from from up to, but not including, to. Pass nil for a range without a lower or upper
+// bound.
+func (p *Partition) Range(from, to interface{}) *Partition {
+	p.partitionDef.From = from
+	p.partitionDef.To = to
+	return p
+}
+
+// List holds rows with one of the given values
+func (p *Partition) List(values ...interface{}) *Partition {
+	p.partitionDef.In = values
+	return p
+}
+
+// Hash holds the rows whose hash has the given remainder when divided by modulus. MySQL assigns rows to its hash
+// partitions itself and ignores these values.
+func (p *Partition) Hash(modulus, remainder int) *Partition {
+	p.partitionDef.Modulus = modulus
+	p.partitionDef.Remainder = remainder
+	return p
+}
+
+// Default holds rows that don't belong to any other partition. Hash partitioned tables don't have one and MySQL only
+// has default range partitions, which hold values less than MAXVALUE.
+func (p *Partition) Default() *Partition {
+	p.partitionDef.IsDefault = true
+	return p
+}
@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"errors"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+// Reverse builds a schema that undoes this one by reversing each operation, starting with the last. Created tables,
+// partitions, views, triggers, types and sequences are dropped, attached partitions are detached, grants are revoked,
+// added columns, indices, constraints and policies are removed and renamed columns get their original name back.
+// Dropping anything, creating extensions, revoking privileges, adding enum values and raw statements can't be reversed
+// and return errors wrapping ErrIrreversible.
+func (s *SchemaDef) Reverse() (*Schema, error) {
+	down := New(s.Driver, s.Name)
+	errs := []error{}
+	// enum types of dropped tables are dropped after the tables, which are dropped together
+	var enums []*EnumDef
+	for i := len(s.Operations) - 1; i >= 0; i-- {
+		op := s.Operations[i]
+		if op.Kind != OpCreateTable {
+			for _, enum := range enums {
+				down.DropEnum(enum.Name)
+			}
+			enums = nil
+		}
+		switch op.Kind {
+		case OpCreateTable:
+			// SQLite FTS5 tables aren't dropped with the table they index
+			if len(op.Table.Searches) > 0 && s.Driver == driver.TypeSqlite3 {
+				down.Table(op.Table.Name, func(dt *Table) {
+					for _, search := range op.Table.Searches {
+						dt.DropSearch(search.Name)
+					}
+				})
+			}
+			down.Drop(op.Table.Name)
+			enums = append(enums, op.Table.enums()...)
+		case OpAlterTable:
+			errs = append(errs, op.Table.reverse(down)...)
+			for _, enum := range op.Table.enums() {
+				down.DropEnum(enum.Name)
+			}
+		case OpCreateView:
+			switch {
+			case op.View.OrReplace:
+				errs = append(errs, irreversible("replacing view %s", op.View.Name))
+			case op.View.Materialized:
+				down.DropMaterializedView(op.View.Name)
+			default:
+				down.DropView(op.View.Name)
+			}
+		case OpRefreshView:
+		case OpCreateTrigger:
+			down.DropTrigger(op.Trigger.Name, op.Trigger.Table)
+		case OpCreateEnum:
+			down.DropEnum(op.Enum.Name)
+		case OpCreateSequence:
+			down.DropSequence(op.Sequence.Name)
+		case OpCreateType:
+			down.DropType(op.Type.Name)
+		case OpCreateDomain:
+			down.DropDomain(op.Domain.Name)
+		case OpCreatePartition:
+			down.DropPartition(op.Partition.Table, op.Partition.Name)
+		case OpAttachPartition:
+			down.DetachPartition(op.Partition.Table, op.Partition.Name)
+		case OpExec:
+			statement, _ := op.Exec.Statement()
+			errs = append(errs, irreversible("exec %q", statement.Sql))
+		case OpDropTable, OpDropIndex:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Name))
+		case OpDropView:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.View.Name))
+		case OpDropTrigger:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Trigger.Name))
+		case OpAddEnumValue, OpDropEnum:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Enum.Name))
+		case OpRestartSequence, OpDropSequence:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Sequence.Name))
+		case OpCreateExtension, OpDropExtension:
+			// the extension may have been installed before the schema ran
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Extension.Name))
+		case OpDropType:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Type.Name))
+		case OpDropDomain:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Domain.Name))
+		case OpDetachPartition, OpDropPartition:
+			errs = append(errs, irreversible("%s %s", op.Kind, op.Partition.Name))
+		case OpGrant:
+			grant := *op.Grant
+			grant.Schema = down.Schema
+			down.Schema.add(Operation{Kind: OpRevoke, Grant: &grant})
+		case OpRevoke:
+			// the privileges the role held before aren't known, so granting them back could give it more
+			errs = append(errs, irreversible("%s %s on %s", op.Kind, op.Grant.Role, op.Grant.Name))
+		default:
+			errs = append(errs, irreversible("%s", op.Kind))
+		}
+	}
+	for _, enum := range enums {
+		down.DropEnum(enum.Name)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return down, nil
+}
+
+// reverse undoes the changes made to an existing table
+func (t *TableDef) reverse(down *Schema) (errs []error) {
+	if t.Comment != "" {
+		errs = append(errs, irreversible("commenting table %s", t.Name))
+	}
+	for _, name := range t.DroppingChecks {
+		errs = append(errs, irreversible("dropping check %s", name))
+	}
+	for _, name := range t.DroppingColumns {
+		errs = append(errs, irreversible("dropping column %s.%s", t.Name, name))
+	}
+	for _, name := range t.DroppingIndices {
+		errs = append(errs, irreversible("dropping index %s", name))
+	}
+	for _, name := range t.DroppingForeigns {
+		errs = append(errs, irreversible("dropping foreign key %s", name))
+	}
+	for _, name := range t.DroppingSearches {
+		errs = append(errs, irreversible("dropping search %s", name))
+	}
+	for _, name := range t.DroppingPolicies {
+		errs = append(errs, irreversible("dropping policy %s", name))
+	}
+	// forcing is only reversed along with enabling or disabling, which assumes the whole row security setup changes
+	if t.ForceRowSecurity && !t.EnableRowSecurity {
+		errs = append(errs, irreversible("forcing row security on %s", t.Name))
+	}
+	if t.NoForceRowSecurity && !t.DisableRowSecurity {
+		errs = append(errs, irreversible("no longer forcing row security on %s", t.Name))
+	}
+	down.Table(t.Name, func(dt *Table) {
+		for _, p := range t.Policies {
+			dt.DropPolicy(p.Name)
+		}
+		if t.EnableRowSecurity {
+			dt.DisableRowSecurity()
+		}
+		if t.ForceRowSecurity {
+			dt.NoForceRowSecurity()
+		}
+		if t.DisableRowSecurity {
+			dt.EnableRowSecurity()
+		}
+		if t.NoForceRowSecurity {
+			dt.ForceRowSecurity()
+		}
+		for _, search := range t.Searches {
+			dt.DropSearch(search.Name)
+		}
+		for _, idx := range t.Indices {
+			dt.DropIndex(idx.GetName())
+		}
+		for _, f := range t.ForeignKeys {
+			dt.DropForeign(f.GetName())
+		}
+		for _, c := range t.Checks {
+			if c.Name == "" {
+				errs = append(errs, irreversible("unnamed check on %s", t.Name))
+				continue
+			}
+			dt.DropCheck(c.Name)
+		}
+		for _, col := range t.Columns {
+			if col.IsModified {
+				errs = append(errs, irreversible("modifying column %s.%s", t.Name, col.Name))
+				continue
+			}
+			if col.OriginalName != col.Name {
+				dt.Column(col.Name).Name(col.OriginalName)
+				continue
+			}
+			// SQLite column references are part of the column and are dropped with it
+			if col.ReferenceTo != nil && t.Schema.Driver != driver.TypeSqlite3 {
+				dt.DropForeign(col.ForeignName())
+			}
+			dt.DropColumn(col.Name)
+		}
+	})
+	return
+}
@@ -1,8 +1,8 @@
 package schema
 
 import (
-	"crypto/md5"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -23,25 +23,34 @@ func New(driver driver.Type, name string) *Schema {
 type Statement struct {
 	Sql    string
 	Params []interface{}
+	// run replaces executing Sql for steps that need to inspect the database first, like SQLite table rebuilds
+	run func(tx *sql.Tx) error
 }
 
 type SchemaDef struct {
-	Driver          driver.Type
-	Name            string
-	Tables          []*TableDef
-	Execs           []Statement
-	DroppingTables  []string
-	DroppingForeign []string
-	DroppingIndices []string
-	DropCreated     bool
+	Driver     driver.Type
+	Name       string
+	Operations []Operation
+	errs       []error
 }
 
 type Schema struct {
 	Schema *SchemaDef
 }
 
+func (s *SchemaDef) add(op Operation) {
+	s.Operations = append(s.Operations, op)
+}
+
+// Exec runs a statement with the given parameters between the operations before and after it
 func (s *Schema) Exec(statement string, params ...interface{}) {
-	s.Schema.Execs = append(s.Schema.Execs, Statement{Sql: statement, Params: params})
+	s.Schema.add(Operation{Kind: OpExec, Exec: &ExecDef{Schema: s.Schema, Sql: statement, Params: params}})
+}
+
+// ExecByDriver runs the statement written for the schema's driver with the given parameters. Rendering fails if there
+// is no statement for the driver.
+func (s *Schema) ExecByDriver(statements map[driver.Type]string, params ...interface{}) {
+	s.Schema.add(Operation{Kind: OpExec, Exec: &ExecDef{Schema: s.Schema, Variants: statements, Params: params}})
 }
 
 func (s *Schema) Create(table string, fn TableMutator) {
@@ -52,7 +61,7 @@ func (s *Schema) Create(table string, fn TableMutator) {
 			WillCreate: true,
 		},
 	}
-	s.Schema.Tables = append(s.Schema.Tables, builder.tableDef)
+	s.Schema.add(Operation{Kind: OpCreateTable, Table: builder.tableDef})
 	fn(&builder)
 	return
 }
@@ -74,72 +83,562 @@ func (s *Schema) Table(table string, fn TableMutator) {
 			Name:   table,
 		},
 	}
-	s.Schema.Tables = append(s.Schema.Tables, builder.tableDef)
+	s.Schema.add(Operation{Kind: OpAlterTable, Table: builder.tableDef})
 	fn(&builder)
 }
 
 func (s *Schema) Drop(table string) {
-	s.Schema.DroppingTables = append(s.Schema.DroppingTables, table)
+	s.Schema.add(Operation{Kind: OpDropTable, Name: table})
 }
 
+// DropForeign drops the foreign key created by References on the given column
 func (s *Schema) DropForeign(table string, foreign string) {
-	s.Schema.DroppingForeign = append(s.Schema.DroppingForeign, fmt.Sprintf("fk_%s_%s", table, foreign))
+	s.Table(table, func(t *Table) {
+		t.DropForeign(fmt.Sprintf("fk_%s_%s", table, foreign))
+	})
 }
 
+// DropIndex drops an index by name. MySQL needs the table of the index, so use Table.DropIndex instead.
 func (s *Schema) DropIndex(name string) {
-	s.Schema.DroppingIndices = append(s.Schema.DroppingIndices, name)
+	s.Schema.add(Operation{Kind: OpDropIndex, Name: name})
+}
+
+// CreateView creates a view using the given SELECT statement
+func (s *Schema) CreateView(name, query string) {
+	s.Schema.add(Operation{Kind: OpCreateView, View: &ViewDef{Schema: s.Schema, Name: name, Query: query}})
+}
+
+// CreateOrReplaceView creates a view or replaces the query of an existing view with the same name
+func (s *Schema) CreateOrReplaceView(name, query string) {
+	s.Schema.add(Operation{Kind: OpCreateView, View: &ViewDef{Schema: s.Schema, Name: name, Query: query, OrReplace: true}})
+}
+
+// CreateMaterializedView creates a view that stores the result of its query. Only Postgres supports materialized
+// views.
+func (s *Schema) CreateMaterializedView(name, query string) {
+	s.Schema.add(Operation{Kind: OpCreateView, View: &ViewDef{Schema: s.Schema, Name: name, Query: query, Materialized: true}})
+}
+
+// RefreshMaterializedView replaces the stored result of a materialized view
+func (s *Schema) RefreshMaterializedView(name string) {
+	s.Schema.add(Operation{Kind: OpRefreshView, View: &ViewDef{Schema: s.Schema, Name: name, Materialized: true}})
+}
+
+func (s *Schema) DropView(name string) {
+	s.Schema.add(Operation{Kind: OpDropView, View: &ViewDef{Schema: s.Schema, Name: name}})
+}
+
+func (s *Schema) DropMaterializedView(name string) {
+	s.Schema.add(Operation{Kind: OpDropView, View: &ViewDef{Schema: s.Schema, Name: name, Materialized: true}})
+}
+
+// CreateTrigger creates a trigger on the given table. Row level triggers are created for every dialect.
+func (s *Schema) CreateTrigger(name, table string, fn TriggerMutator) {
+	builder := Trigger{
+		triggerDef: &TriggerDef{
+			Schema: s.Schema,
+			Name:   name,
+			Table:  table,
+			Bodies: map[driver.Type]string{},
+		},
+	}
+	s.Schema.add(Operation{Kind: OpCreateTrigger, Trigger: builder.triggerDef})
+	fn(&builder)
+}
+
+// DropTrigger drops a trigger. Postgres needs the table of the trigger and also drops the trigger's function.
+func (s *Schema) DropTrigger(name, table string) {
+	s.Schema.add(Operation{Kind: OpDropTrigger, Trigger: &TriggerDef{Schema: s.Schema, Name: name, Table: table}})
+}
+
+// CreateEnum creates a Postgres enum type that can be shared by columns using columnBuilder.EnumType. Other drivers
+// declare enum values on each column instead.
+func (s *Schema) CreateEnum(name string, values ...interface{}) {
+	s.Schema.add(Operation{Kind: OpCreateEnum, Enum: &EnumDef{Schema: s.Schema, Name: name, Values: values}})
+}
+
+// AddEnumValue adds values to an existing Postgres enum type. Postgres older than 12 can't add enum values inside a
+// transaction.
+func (s *Schema) AddEnumValue(name string, values ...interface{}) {
+	s.Schema.add(Operation{Kind: OpAddEnumValue, Enum: &EnumDef{Schema: s.Schema, Name: name, Values: values}})
+}
+
+func (s *Schema) DropEnum(name string) {
+	s.Schema.add(Operation{Kind: OpDropEnum, Enum: &EnumDef{Schema: s.Schema, Name: name}})
+}
+
+// CreateSequence creates a standalone Postgres sequence. SQLite and MySQL don't support sequences.
+func (s *Schema) CreateSequence(name string, fn SequenceMutator) {
+	builder := Sequence{sequenceDef: &SequenceDef{Schema: s.Schema, Name: name}}
+	s.Schema.add(Operation{Kind: OpCreateSequence, Sequence: builder.sequenceDef})
+	if fn != nil {
+		fn(&builder)
+	}
+}
+
+// RestartSequence sets the next value returned by a sequence
+func (s *Schema) RestartSequence(name string, value int64) {
+	s.Schema.add(Operation{Kind: OpRestartSequence, Sequence: &SequenceDef{Schema: s.Schema, Name: name, Restart: value}})
+}
+
+func (s *Schema) DropSequence(name string) {
+	s.Schema.add(Operation{Kind: OpDropSequence, Sequence: &SequenceDef{Schema: s.Schema, Name: name}})
+}
+
+// CreateExtension installs a Postgres extension unless it's already installed. Since it may have been installed before,
+// the extension isn't removed by DropCreated or Reverse.
+func (s *Schema) CreateExtension(name string) {
+	s.Schema.add(Operation{Kind: OpCreateExtension, Extension: &ExtensionDef{Schema: s.Schema, Name: name}})
+}
+
+func (s *Schema) DropExtension(name string) {
+	s.Schema.add(Operation{Kind: OpDropExtension, Extension: &ExtensionDef{Schema: s.Schema, Name: name}})
+}
+
+// CreateType creates a Postgres composite type that columns can use with columnBuilder.CustomType
+func (s *Schema) CreateType(name string, fn TypeMutator) {
+	builder := CompositeType{typeDef: &TypeDef{Schema: s.Schema, Name: name}}
+	s.Schema.add(Operation{Kind: OpCreateType, Type: builder.typeDef})
+	fn(&builder)
+}
+
+func (s *Schema) DropType(name string) {
+	s.Schema.add(Operation{Kind: OpDropType, Type: &TypeDef{Schema: s.Schema, Name: name}})
+}
+
+// CreateDomain creates a Postgres domain based on sqlType that columns can use with columnBuilder.CustomType
+func (s *Schema) CreateDomain(name, sqlType string, fn DomainMutator) {
+	builder := Domain{domainDef: &DomainDef{Schema: s.Schema, Name: name, Type: sqlType}}
+	s.Schema.add(Operation{Kind: OpCreateDomain, Domain: builder.domainDef})
+	if fn != nil {
+		fn(&builder)
+	}
+}
+
+func (s *Schema) DropDomain(name string) {
+	s.Schema.add(Operation{Kind: OpDropDomain, Domain: &DomainDef{Schema: s.Schema, Name: name}})
+}
+
+// CreatePartition adds a partition to a partitioned table
+func (s *Schema) CreatePartition(table, name string, fn PartitionMutator) {
+	builder := Partition{partitionDef: &PartitionDef{Schema: s.Schema, Table: table, Name: name}}
+	s.Schema.add(Operation{Kind: OpCreatePartition, Partition: builder.partitionDef})
+	if fn != nil {
+		fn(&builder)
+	}
+}
+
+// AttachPartition makes an existing Postgres table a partition of a partitioned table
+func (s *Schema) AttachPartition(table, name string, fn PartitionMutator) {
+	builder := Partition{partitionDef: &PartitionDef{Schema: s.Schema, Table: table, Name: name}}
+	s.Schema.add(Operation{Kind: OpAttachPartition, Partition: builder.partitionDef})
+	if fn != nil {
+		fn(&builder)
+	}
+}
+
+// DetachPartition turns a Postgres partition into a table of its own, keeping its rows
+func (s *Schema) DetachPartition(table, name string) {
+	s.Schema.add(Operation{Kind: OpDetachPartition, Partition: &PartitionDef{Schema: s.Schema, Table: table, Name: name}})
+}
+
+// DropPartition drops a partition along with its rows
+func (s *Schema) DropPartition(table, name string) {
+	s.Schema.add(Operation{Kind: OpDropPartition, Partition: &PartitionDef{Schema: s.Schema, Table: table, Name: name}})
+}
+
+// Grant gives a role privileges on a table, like SELECT or INSERT. All privileges are granted if none are given.
+func (s *Schema) Grant(table, role string, privileges ...string) {
+	s.Schema.add(Operation{Kind: OpGrant, Grant: &GrantDef{Schema: s.Schema, Object: GrantTable, Name: table, Role: role, Privileges: privileges}})
+}
+
+// GrantSequence gives a role privileges on a Postgres sequence, like USAGE or SELECT
+func (s *Schema) GrantSequence(sequence, role string, privileges ...string) {
+	s.Schema.add(Operation{Kind: OpGrant, Grant: &GrantDef{Schema: s.Schema, Object: GrantSequence, Name: sequence, Role: role, Privileges: privileges}})
+}
+
+// Revoke takes privileges on a table away from a role. All privileges are revoked if none are given.
+func (s *Schema) Revoke(table, role string, privileges ...string) {
+	s.Schema.add(Operation{Kind: OpRevoke, Grant: &GrantDef{Schema: s.Schema, Object: GrantTable, Name: table, Role: role, Privileges: privileges}})
+}
+
+// RevokeSequence takes privileges on a Postgres sequence away from a role
+func (s *Schema) RevokeSequence(sequence, role string, privileges ...string) {
+	s.Schema.add(Operation{Kind: OpRevoke, Grant: &GrantDef{Schema: s.Schema, Object: GrantSequence, Name: sequence, Role: role, Privileges: privileges}})
 }
 
+// DropCreated drops everything created by the operations before it, in reverse
 func (s *Schema) DropCreated() {
-	s.Schema.DropCreated = true
+	s.Schema.add(Operation{Kind: OpDropCreated})
+}
+
+// Statements renders the schema without running it. Errors from building the schema are returned together.
+func (s *SchemaDef) Statements() (statements []string, err error) {
+	steps, err := s.steps()
+	for _, statement := range steps {
+		statements = append(statements, statement.Sql)
+	}
+	return
+}
+
+// dropTables drops tables that may reference each other. MySQL and Postgres resolve the references between tables
+// dropped by the same statement. SQLite drops one table at a time, so checking foreign keys is deferred until commit.
+func (s *SchemaDef) dropTables(tables []string) (statements []Statement, err error) {
+	if len(tables) == 0 {
+		return
+	}
+	tmp, err := s.loadTemplates()
+	if err != nil {
+		return
+	}
+	if s.Driver != driver.TypeSqlite3 {
+		sql, err := execTemplate(tmp, "drop_tables", tables)
+		return []Statement{{Sql: sql}}, err
+	}
+	if len(tables) > 1 {
+		statements = append(statements, Statement{Sql: "PRAGMA defer_foreign_keys = ON"})
+	}
+	for _, table := range tables {
+		var sql string
+		if sql, err = execTemplate(tmp, "drop_tables", []string{table}); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
+	}
+	return
 }
 
-func (s *SchemaDef) Statements() (statements []string) {
-	for _, table := range s.Tables {
-		statements = append(statements, table.Statements()...)
+// dropCreated drops the tables, partitions, views, triggers, types and sequences created by the given operations
+// since the last DropCreated. Anything already dropped by a later operation is left alone.
+func (s *SchemaDef) dropCreated(ops []Operation) (statements []Statement, err error) {
+	tmp, err := s.loadTemplates()
+	if err != nil {
+		return
+	}
+	var sql string
+	var sqls []string
+	var tables []*TableDef
+	var enums []*EnumDef
+	var sequences []*SequenceDef
+	// types and domains are dropped last since tables may use them
+	var types []Statement
+	// dropped is keyed by the kind of object and its name
+	dropped := map[string]bool{}
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		if op.Kind == OpDropCreated {
+			break
+		}
+		if key := droppedKey(op); key != "" {
+			dropped[key] = true
+			continue
+		}
+		if key := createdKey(op); key != "" && dropped[key] {
+			continue
+		}
+		switch op.Kind {
+		case OpCreatePartition:
+			if statements, err = s.appendStatement(statements, tmp, "drop_partition", op.Partition); err != nil {
+				return
+			}
+		case OpCreateTrigger:
+			if sqls, err = op.Trigger.dropStatements(); err != nil {
+				return
+			}
+			for _, sql := range sqls {
+				statements = append(statements, Statement{Sql: sql})
+			}
+		case OpCreateView:
+			if sql, err = op.View.dropStatement(); err != nil {
+				return
+			}
+			statements = append(statements, Statement{Sql: sql})
+		case OpCreateTable, OpAlterTable:
+			tables = append([]*TableDef{op.Table}, tables...)
+			// altered tables are kept, so the enum types of their columns are still in use
+			if op.Kind == OpAlterTable {
+				continue
+			}
+			enums = append(enums, op.Table.enums()...)
+			// SQLite FTS5 tables aren't dropped with the table they index
+			if s.Driver == driver.TypeSqlite3 {
+				var steps []Statement
+				if steps, err = op.Table.dropSearches(); err != nil {
+					return
+				}
+				statements = append(statements, steps...)
+			}
+		case OpCreateEnum:
+			enums = append(enums, op.Enum)
+		case OpCreateSequence:
+			sequences = append(sequences, op.Sequence)
+		case OpCreateType:
+			if types, err = s.appendStatement(types, tmp, "drop_type", op.Type); err != nil {
+				return
+			}
+		case OpCreateDomain:
+			if types, err = s.appendStatement(types, tmp, "drop_domain", op.Domain); err != nil {
+				return
+			}
+		}
+	}
+	// tables are dropped before the tables they reference. Indices and foreign keys are dropped with their table.
+	ordered, _ := s.orderTables(tables)
+	names := []string{}
+	for i := len(ordered) - 1; i >= 0; i-- {
+		if ordered[i].WillCreate && !dropped["table:"+ordered[i].Name] {
+			names = append(names, ordered[i].Name)
+		}
+	}
+	steps, err := s.dropTables(names)
+	if err != nil {
+		return
+	}
+	statements = append(statements, steps...)
+	for _, enum := range enums {
+		if sql, err = enum.dropStatement(); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
 	}
-	statements = append(statements, s.DropStatements()...)
+	for _, name := range names {
+		dropped["table:"+name] = true
+	}
+	for _, sequence := range sequences {
+		// sequences owned by a column are dropped with its table
+		if dropped["table:"+sequence.OwnedTable] {
+			continue
+		}
+		if statements, err = s.appendStatement(statements, tmp, "drop_sequence", sequence); err != nil {
+			return
+		}
+	}
+	statements = append(statements, types...)
 	return
 }
 
-func (s *SchemaDef) DropStatements() (statements []string) {
-	if s.DropCreated {
-		for _, table := range s.Tables {
-			statements = append(statements, fmt.Sprintf("DROP TABLE `%s`", table.Name))
-			// TODO: drop indices and foreign keys
+// droppedKey identifies the object removed by an explicit drop operation
+func droppedKey(op Operation) string {
+	switch op.Kind {
+	case OpDropTable:
+		return "table:" + op.Name
+	case OpDropPartition:
+		return "table:" + op.Partition.Name
+	case OpDropView:
+		return "view:" + op.View.Name
+	case OpDropTrigger:
+		return "trigger:" + op.Trigger.Table + "." + op.Trigger.Name
+	case OpDropEnum:
+		return "enum:" + op.Enum.Name
+	case OpDropSequence:
+		return "sequence:" + op.Sequence.Name
+	case OpDropType:
+		return "type:" + op.Type.Name
+	case OpDropDomain:
+		return "domain:" + op.Domain.Name
+	}
+	return ""
+}
+
+// createdKey identifies the object added by a create operation the same way droppedKey does
+func createdKey(op Operation) string {
+	switch op.Kind {
+	case OpCreateTable:
+		return "table:" + op.Table.Name
+	case OpCreatePartition:
+		return "table:" + op.Partition.Name
+	case OpCreateView:
+		return "view:" + op.View.Name
+	case OpCreateTrigger:
+		return "trigger:" + op.Trigger.Table + "." + op.Trigger.Name
+	case OpCreateEnum:
+		return "enum:" + op.Enum.Name
+	case OpCreateSequence:
+		return "sequence:" + op.Sequence.Name
+	case OpCreateType:
+		return "type:" + op.Type.Name
+	case OpCreateDomain:
+		return "domain:" + op.Domain.Name
+	}
+	return ""
+}
+
+// createdTable returns the definition of a table created by this schema
+func (s *SchemaDef) createdTable(name string) *TableDef {
+	for _, op := range s.Operations {
+		if op.Kind == OpCreateTable && op.Table.Name == name {
+			return op.Table
+		}
+	}
+	return nil
+}
+
+// addError records a mistake made while building the schema. It is returned when the schema is rendered.
+func (s *SchemaDef) addError(err error) {
+	s.errs = append(s.errs, err)
+}
+
+// validate returns every error found in the schema instead of stopping at the first one
+func (s *SchemaDef) validate() error {
+	errs := append([]error{}, s.errs...)
+	for _, op := range s.Operations {
+		if op.Kind == OpCreatePartition || op.Kind == OpAttachPartition {
+			var method PartitionMethod
+			if t := s.createdTable(op.Partition.Table); t != nil && t.Partitioning != nil {
+				method = t.Partitioning.Method
+			}
+			errs = append(errs, op.Partition.validate(method)...)
+		}
+		if op.Table == nil {
+			continue
 		}
-	} else {
-		for _, index := range s.DroppingIndices {
-			statements = append(statements, fmt.Sprintf("DROP INDEX `%s`", index))
+		if err := op.Table.validate(); err != nil {
+			errs = append(errs, err)
 		}
-		for _, foreign := range s.DroppingForeign {
-			statements = append(statements, fmt.Sprintf("DROP FOREIGN KEY `%s`", foreign))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *SchemaDef) steps() (statements []Statement, err error) {
+	if err = s.validate(); err != nil {
+		return
+	}
+	ops := s.Operations
+	for i := 0; i < len(ops); {
+		j := i + 1
+		for j < len(ops) && ops[i].Kind.grouped(ops[j].Kind) {
+			j++
 		}
-		for _, table := range s.DroppingTables {
-			statements = append(statements, fmt.Sprintf("DROP TABLE `%s`", table))
+		var steps []Statement
+		if steps, err = s.render(ops[i:j], ops[:i]); err != nil {
+			return
 		}
+		statements = append(statements, steps...)
+		i = j
 	}
 	return
 }
 
-func (s *SchemaDef) Hash() (hash []byte, err error) {
-	sum := md5.New()
-	for _, statement := range s.Statements() {
-		if _, err = sum.Write([]byte(statement)); err != nil {
+// render renders a group of operations of the same kind. Previous operations are needed by DropCreated.
+func (s *SchemaDef) render(ops []Operation, previous []Operation) (statements []Statement, err error) {
+	tmp, err := s.loadTemplates()
+	if err != nil {
+		return
+	}
+	var sql string
+	var sqls []string
+	op := ops[0]
+	switch op.Kind {
+	case OpCreateTable, OpAlterTable:
+		tables := []*TableDef{}
+		for _, op := range ops {
+			tables = append(tables, op.Table)
+		}
+		// referenced tables are created first and foreign keys in a reference cycle are added once both tables exist
+		ordered, deferred := s.orderTables(tables)
+		for _, table := range ordered {
+			var steps []Statement
+			if steps, err = table.steps(); err != nil {
+				return
+			}
+			statements = append(statements, steps...)
+		}
+		for _, f := range deferred {
+			if sql, err = execTemplate(tmp, "add_foreign", f); err != nil {
+				return
+			}
+			statements = append(statements, Statement{Sql: sql})
+		}
+		return
+	case OpDropTable:
+		names := []string{}
+		for _, op := range ops {
+			names = append(names, op.Name)
+		}
+		return s.dropTables(names)
+	case OpDropCreated:
+		return s.dropCreated(previous)
+	case OpExec:
+		var statement Statement
+		if statement, err = op.Exec.Statement(); err != nil {
 			return
 		}
+		return []Statement{statement}, nil
+	case OpCreateView:
+		return op.View.steps()
+	case OpCreateTrigger:
+		return op.Trigger.steps()
+	case OpDropTrigger:
+		sqls, err = op.Trigger.dropStatements()
+	case OpAddEnumValue:
+		sqls, err = op.Enum.addValueStatements()
+	case OpRefreshView:
+		sql, err = op.View.refreshStatement()
+	case OpDropView:
+		sql, err = op.View.dropStatement()
+	case OpCreateEnum:
+		sql, err = op.Enum.createStatement()
+	case OpDropEnum:
+		sql, err = op.Enum.dropStatement()
+	case OpCreateSequence:
+		statements, err = s.appendStatement(statements, tmp, "create_sequence", op.Sequence)
+	case OpRestartSequence:
+		statements, err = s.appendStatement(statements, tmp, "restart_sequence", op.Sequence)
+	case OpDropSequence:
+		statements, err = s.appendStatement(statements, tmp, "drop_sequence", op.Sequence)
+	case OpCreateExtension:
+		statements, err = s.appendStatement(statements, tmp, "create_extension", op.Extension)
+	case OpDropExtension:
+		statements, err = s.appendStatement(statements, tmp, "drop_extension", op.Extension)
+	case OpCreateType:
+		statements, err = s.appendStatement(statements, tmp, "create_type", op.Type)
+	case OpDropType:
+		statements, err = s.appendStatement(statements, tmp, "drop_type", op.Type)
+	case OpCreateDomain:
+		statements, err = s.appendStatement(statements, tmp, "create_domain", op.Domain)
+	case OpDropDomain:
+		statements, err = s.appendStatement(statements, tmp, "drop_domain", op.Domain)
+	case OpCreatePartition:
+		statements, err = s.appendStatement(statements, tmp, "create_partition", op.Partition)
+	case OpAttachPartition:
+		statements, err = s.appendStatement(statements, tmp, "attach_partition", op.Partition)
+	case OpDetachPartition:
+		statements, err = s.appendStatement(statements, tmp, "detach_partition", op.Partition)
+	case OpDropPartition:
+		statements, err = s.appendStatement(statements, tmp, "drop_partition", op.Partition)
+	case OpGrant:
+		statements, err = s.appendStatement(statements, tmp, "grant", op.Grant)
+	case OpRevoke:
+		statements, err = s.appendStatement(statements, tmp, "revoke", op.Grant)
+	case OpDropIndex:
+		sql, err = execTemplate(tmp, "drop_index", &indexDef{Name: op.Name})
+	default:
+		err = fmt.Errorf("unknown operation %s", op.Kind)
+	}
+	if sql != "" {
+		sqls = append(sqls, sql)
+	}
+	for _, sql := range sqls {
+		statements = append(statements, Statement{Sql: sql})
 	}
-	hash = sum.Sum(nil)
 	return
 }
 
+// Run executes the schema in the transaction. Nothing is executed if the schema has errors.
 func (s *SchemaDef) Run(tx *sql.Tx, logger *slog.Logger) (err error) {
-	statements := s.Statements()
-	for _, statement := range statements {
+	steps, err := s.steps()
+	if err != nil {
+		return
+	}
+	for _, statement := range steps {
 		if logger != nil {
-			logger.Info("executing", "statement", statement)
+			logger.Info("executing", "statement", statement.Sql)
+		}
+		if statement.run != nil {
+			err = statement.run(tx)
+		} else {
+			_, err = tx.Exec(statement.Sql, statement.Params...)
 		}
-		_, err = tx.Exec(statement)
 		if err != nil {
 			return
 		}
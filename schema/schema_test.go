@@ -0,0 +1,834 @@
+package schema
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+type testSchemaStatement struct {
+	Name           string
+	Mutate         func(s *Schema)
+	SqliteResult   string
+	MysqlResult    string
+	PostgresResult string
+}
+
+var schemaStatements = []testSchemaStatement{
+	{
+		Name: "create_view",
+		Mutate: func(s *Schema) {
+			s.CreateView("active_user", "SELECT * FROM user WHERE active")
+		},
+		SqliteResult:   "CREATE VIEW `active_user` AS SELECT * FROM user WHERE active;",
+		MysqlResult:    "CREATE VIEW `active_user` AS SELECT * FROM user WHERE active;",
+		PostgresResult: "CREATE VIEW \"active_user\" AS SELECT * FROM user WHERE active;",
+	},
+	{
+		Name: "create_or_replace_view",
+		Mutate: func(s *Schema) {
+			s.CreateOrReplaceView("active_user", "SELECT * FROM user WHERE active")
+		},
+		SqliteResult:   "DROP VIEW IF EXISTS `active_user`;CREATE VIEW `active_user` AS SELECT * FROM user WHERE active;",
+		MysqlResult:    "CREATE OR REPLACE VIEW `active_user` AS SELECT * FROM user WHERE active;",
+		PostgresResult: "CREATE OR REPLACE VIEW \"active_user\" AS SELECT * FROM user WHERE active;",
+	},
+	{
+		Name: "materialized_view",
+		Mutate: func(s *Schema) {
+			s.CreateMaterializedView("user_count", "SELECT count(*) FROM user")
+			s.RefreshMaterializedView("user_count")
+		},
+		PostgresResult: "CREATE MATERIALIZED VIEW \"user_count\" AS SELECT count(*) FROM user;REFRESH MATERIALIZED VIEW \"user_count\";",
+	},
+	{
+		Name: "call_order",
+		Mutate: func(s *Schema) {
+			s.DropView("old_user")
+			s.CreateView("active_user", "SELECT * FROM user WHERE active")
+			s.Create("user", func(t *Table) {
+				t.Boolean("active")
+			})
+		},
+		SqliteResult: "DROP VIEW `old_user`;CREATE VIEW `active_user` AS SELECT * FROM user WHERE active;CREATE TABLE `user` ('active' INTEGER NOT NULL);",
+	},
+	{
+		Name: "exec_between_tables",
+		Mutate: func(s *Schema) {
+			s.Create("role", func(t *Table) {
+				t.String("name")
+			})
+			s.Exec("INSERT INTO role (name) VALUES (?)", "admin")
+			s.Table("role", func(t *Table) {
+				t.Integer("level").Default(0)
+			})
+		},
+		SqliteResult: "CREATE TABLE `role` ('name' TEXT NOT NULL);INSERT INTO role (name) VALUES (?);ALTER TABLE `role` ADD COLUMN 'level' INTEGER NOT NULL DEFAULT 0;",
+	},
+	{
+		Name: "drop_created_views",
+		Mutate: func(s *Schema) {
+			s.CreateView("active_user", "SELECT * FROM user WHERE active")
+			s.CreateMaterializedView("user_count", "SELECT count(*) FROM user")
+			s.DropCreated()
+		},
+		PostgresResult: "CREATE VIEW \"active_user\" AS SELECT * FROM user WHERE active;CREATE MATERIALIZED VIEW \"user_count\" AS SELECT count(*) FROM user;DROP MATERIALIZED VIEW \"user_count\";DROP VIEW \"active_user\";",
+	},
+	{
+		Name: "create_trigger",
+		Mutate: func(s *Schema) {
+			s.CreateTrigger("user_updated_at", "user", func(t *Trigger) {
+				t.After(TriggerUpdate).
+					Body(driver.TypeSqlite3, "UPDATE user SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id").
+					Body(driver.TypeMysql, "INSERT INTO audit (user_id) VALUES (NEW.id);").
+					Body(driver.TypePostgres, "NEW.updated_at = now();")
+			})
+		},
+		SqliteResult:   "CREATE TRIGGER `user_updated_at` AFTER UPDATE ON `user` FOR EACH ROW\nBEGIN\nUPDATE user SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;\nEND;",
+		MysqlResult:    "CREATE TRIGGER `user_updated_at` AFTER UPDATE ON `user` FOR EACH ROW\nBEGIN\nINSERT INTO audit (user_id) VALUES (NEW.id);\nEND;",
+		PostgresResult: "CREATE OR REPLACE FUNCTION \"user_updated_at_fn\"() RETURNS trigger AS $$\nBEGIN\nNEW.updated_at = now();\nRETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;CREATE TRIGGER \"user_updated_at\" AFTER UPDATE ON \"user\" FOR EACH ROW EXECUTE FUNCTION \"user_updated_at_fn\"();",
+	},
+	{
+		Name: "drop_trigger",
+		Mutate: func(s *Schema) {
+			s.DropTrigger("user_updated_at", "user")
+		},
+		SqliteResult:   "DROP TRIGGER `user_updated_at`;",
+		MysqlResult:    "DROP TRIGGER `user_updated_at`;",
+		PostgresResult: "DROP TRIGGER \"user_updated_at\" ON \"user\";DROP FUNCTION \"user_updated_at_fn\"();",
+	},
+	{
+		Name: "shared_enum",
+		Mutate: func(s *Schema) {
+			s.CreateEnum("mood", "happy", "sad")
+			s.Create("user", func(t *Table) {
+				t.Column("mood").EnumType("mood")
+			})
+			s.DropCreated()
+		},
+		PostgresResult: "CREATE TYPE \"mood\" AS ENUM ('happy', 'sad');CREATE TABLE \"user\" (\n\"mood\" \"mood\" NOT NULL);DROP TABLE \"user\";DROP TYPE \"mood\";",
+	},
+	{
+		Name: "shared_column_enum",
+		Mutate: func(s *Schema) {
+			s.Create("user", func(t *Table) {
+				t.Column("mood", Enum("happy", "sad")).EnumType("shared")
+				t.Column("last_mood", Enum("happy", "sad")).EnumType("shared")
+			})
+			s.Create("post", func(t *Table) {
+				t.Column("mood", Enum("happy", "sad")).EnumType("shared")
+			})
+			s.DropCreated()
+		},
+		PostgresResult: "CREATE TYPE \"shared\" AS ENUM ('happy', 'sad');CREATE TABLE \"user\" (\n\"mood\" \"shared\" NOT NULL,\n\"last_mood\" \"shared\" NOT NULL);CREATE TABLE \"post\" (\n\"mood\" \"shared\" NOT NULL);DROP TABLE \"post\", \"user\";DROP TYPE \"shared\";",
+	},
+	{
+		Name: "add_enum_value",
+		Mutate: func(s *Schema) {
+			s.AddEnumValue("mood", "angry", "calm")
+		},
+		PostgresResult: "ALTER TYPE \"mood\" ADD VALUE IF NOT EXISTS 'angry';ALTER TYPE \"mood\" ADD VALUE IF NOT EXISTS 'calm';",
+	},
+	{
+		Name: "sequences",
+		Mutate: func(s *Schema) {
+			s.CreateSequence("invoice_number", func(q *Sequence) {
+				q.Start(1000).Increment(1).Min(1000).Max(999999).Cycle()
+			})
+			s.CreateSequence("order_id_seq", func(q *Sequence) {
+				q.OwnedBy("order", "id")
+			})
+			s.RestartSequence("invoice_number", 5000)
+			s.DropSequence("order_id_seq")
+		},
+		PostgresResult: "CREATE SEQUENCE \"invoice_number\" INCREMENT BY 1 MINVALUE 1000 MAXVALUE 999999 START WITH 1000 CYCLE;CREATE SEQUENCE \"order_id_seq\" OWNED BY \"order\".\"id\";ALTER SEQUENCE \"invoice_number\" RESTART WITH 5000;DROP SEQUENCE \"order_id_seq\";",
+	},
+	{
+		Name: "custom_types",
+		Mutate: func(s *Schema) {
+			s.CreateExtension("citext")
+			s.CreateDomain("positive_int", "INTEGER", func(d *Domain) {
+				d.NotNull().Default(1).NamedCheck("positive", "VALUE > 0")
+			})
+			s.CreateType("address", func(t *CompositeType) {
+				t.Field("street", "TEXT").Field("zip", "VARCHAR(10)")
+			})
+			s.Create("customer", func(t *Table) {
+				t.Column("email").CustomType("citext")
+				t.Column("visits", CustomType("positive_int"))
+				t.Column("address").CustomType("address").Null()
+				t.Column("tags").CustomType("text[]")
+				t.Column("login").CustomType("public.citext")
+			})
+			s.DropCreated()
+		},
+		PostgresResult: "CREATE EXTENSION IF NOT EXISTS \"citext\";CREATE DOMAIN \"positive_int\" AS INTEGER DEFAULT 1 NOT NULL CONSTRAINT \"positive\" CHECK (VALUE > 0);CREATE TYPE \"address\" AS (\"street\" TEXT, \"zip\" VARCHAR(10));CREATE TABLE \"customer\" (\"email\" \"citext\" NOT NULL, \"visits\" \"positive_int\" NOT NULL, \"address\" \"address\" NULL, \"tags\" text[] NOT NULL, \"login\" public.citext NOT NULL);DROP TABLE \"customer\";DROP TYPE \"address\";DROP DOMAIN \"positive_int\";",
+	},
+	{
+		Name: "search",
+		Mutate: func(s *Schema) {
+			s.Table("post", func(t *Table) {
+				t.Search("post_search", "title", "body").Language("simple")
+				t.DropSearch("old_search")
+			})
+		},
+		MysqlResult:    "DROP INDEX `old_search` ON `post`;CREATE FULLTEXT INDEX `post_search` ON `post` (`title`, `body`);",
+		PostgresResult: "ALTER TABLE \"post\" DROP COLUMN \"old_search\";ALTER TABLE \"post\" ADD COLUMN \"post_search\" tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce(\"title\", '') || ' ' || coalesce(\"body\", ''))) STORED;CREATE INDEX \"idx_post_post_search\" ON \"post\" USING gin(\"post_search\");",
+	},
+	{
+		Name: "partitions",
+		Mutate: func(s *Schema) {
+			s.Create("event", func(t *Table) {
+				t.BigInt("id")
+				t.Column("created_at", Date())
+				t.PartitionBy(PartitionRange, "created_at")
+				t.Partition("event_2024", func(p *Partition) {
+					p.Range("2024-01-01", "2025-01-01")
+				})
+			})
+			s.CreatePartition("event", "event_2025", func(p *Partition) {
+				p.Range("2025-01-01", "2026-01-01")
+			})
+			s.CreatePartition("event", "event_future", func(p *Partition) {
+				p.Default()
+			})
+			s.DropPartition("event", "event_2024")
+		},
+		MysqlResult:    "CREATE TABLE `event` (`id` BIGINT NOT NULL, `created_at` DATE NOT NULL) PARTITION BY RANGE COLUMNS(`created_at`) (PARTITION `event_2024` VALUES LESS THAN ('2025-01-01'));ALTER TABLE `event` ADD PARTITION (PARTITION `event_2025` VALUES LESS THAN ('2026-01-01'));ALTER TABLE `event` ADD PARTITION (PARTITION `event_future` VALUES LESS THAN (MAXVALUE));ALTER TABLE `event` DROP PARTITION `event_2024`;",
+		PostgresResult: "CREATE TABLE \"event\" (\"id\" BIGINT NOT NULL, \"created_at\" DATE NOT NULL) PARTITION BY RANGE (\"created_at\");CREATE TABLE \"event_2024\" PARTITION OF \"event\" FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');CREATE TABLE \"event_2025\" PARTITION OF \"event\" FOR VALUES FROM ('2025-01-01') TO ('2026-01-01');CREATE TABLE \"event_future\" PARTITION OF \"event\" DEFAULT;DROP TABLE \"event_2024\";",
+	},
+	{
+		Name: "list_and_hash_partitions",
+		Mutate: func(s *Schema) {
+			s.CreatePartition("account", "account_eu", func(p *Partition) {
+				p.List("de", "fr")
+			})
+			s.CreatePartition("session", "session_0", func(p *Partition) {
+				p.Hash(4, 0)
+			})
+			s.AttachPartition("metric", "metric_low", func(p *Partition) {
+				p.Range(nil, 0)
+			})
+			s.DetachPartition("metric", "metric_old")
+		},
+		PostgresResult: "CREATE TABLE \"account_eu\" PARTITION OF \"account\" FOR VALUES IN ('de', 'fr');CREATE TABLE \"session_0\" PARTITION OF \"session\" FOR VALUES WITH (MODULUS 4, REMAINDER 0);ALTER TABLE \"metric\" ATTACH PARTITION \"metric_low\" FOR VALUES FROM (MINVALUE) TO (0);ALTER TABLE \"metric\" DETACH PARTITION \"metric_old\";",
+	},
+	{
+		Name: "row_security",
+		Mutate: func(s *Schema) {
+			s.Create("invoice", func(t *Table) {
+				t.Integer("tenant_id")
+				t.EnableRowSecurity()
+				t.Policy("tenant_isolation").To("app", "current_user").Using("tenant_id = current_setting('app.tenant_id')::int")
+				t.Policy("no_deletes").For(PolicyDelete).Restrictive().Using("false")
+			})
+			s.Table("payment", func(t *Table) {
+				t.DropPolicy("old_policy")
+				t.ForceRowSecurity()
+			})
+			s.Table("refund", func(t *Table) {
+				t.NoForceRowSecurity()
+			})
+		},
+		PostgresResult: "CREATE TABLE \"invoice\" (\"tenant_id\" INTEGER NOT NULL);ALTER TABLE \"invoice\" ENABLE ROW LEVEL SECURITY;CREATE POLICY \"tenant_isolation\" ON \"invoice\" TO \"app\", CURRENT_USER USING (tenant_id = current_setting('app.tenant_id')::int);CREATE POLICY \"no_deletes\" ON \"invoice\" AS RESTRICTIVE FOR DELETE USING (false);DROP POLICY \"old_policy\" ON \"payment\";ALTER TABLE \"payment\" FORCE ROW LEVEL SECURITY;ALTER TABLE \"refund\" NO FORCE ROW LEVEL SECURITY;",
+	},
+	{
+		Name: "grants",
+		Mutate: func(s *Schema) {
+			s.Grant("invoice", "app", "SELECT", "INSERT")
+			s.Revoke("invoice", "report")
+			s.Grant("invoice", "CURRENT_USER", "SELECT")
+		},
+		MysqlResult:    "GRANT SELECT, INSERT ON `invoice` TO 'app';REVOKE ALL PRIVILEGES ON `invoice` FROM 'report';GRANT SELECT ON `invoice` TO CURRENT_USER;",
+		PostgresResult: "GRANT SELECT, INSERT ON TABLE \"invoice\" TO \"app\";REVOKE ALL PRIVILEGES ON TABLE \"invoice\" FROM \"report\";GRANT SELECT ON TABLE \"invoice\" TO CURRENT_USER;",
+	},
+	{
+		Name: "sequence_grants",
+		Mutate: func(s *Schema) {
+			s.GrantSequence("invoice_number", "app", "USAGE")
+			s.RevokeSequence("invoice_number", "report", "USAGE")
+			s.RevokeSequence("invoice_number", "public")
+		},
+		PostgresResult: "GRANT USAGE ON SEQUENCE \"invoice_number\" TO \"app\";REVOKE USAGE ON SEQUENCE \"invoice_number\" FROM \"report\";REVOKE ALL PRIVILEGES ON SEQUENCE \"invoice_number\" FROM PUBLIC;",
+	},
+	{
+		Name: "drop_created_skips_dropped",
+		Mutate: func(s *Schema) {
+			s.Create("a", func(t *Table) {
+				t.Primary("id")
+			})
+			s.Drop("a")
+			s.DropCreated()
+		},
+		SqliteResult:   "CREATE TABLE `a` ('id' INTEGER PRIMARY KEY);DROP TABLE `a`;",
+		PostgresResult: "CREATE TABLE \"a\" (\"id\" INTEGER PRIMARY KEY);DROP TABLE \"a\";",
+	},
+	{
+		Name: "drop_created_since_last",
+		Mutate: func(s *Schema) {
+			s.Create("a", func(t *Table) {
+				t.Primary("id")
+			})
+			s.DropCreated()
+			s.Create("b", func(t *Table) {
+				t.Primary("id")
+			})
+			s.DropCreated()
+		},
+		SqliteResult: "CREATE TABLE `a` ('id' INTEGER PRIMARY KEY);DROP TABLE `a`;CREATE TABLE `b` ('id' INTEGER PRIMARY KEY);DROP TABLE `b`;",
+	},
+	{
+		Name: "drop_created_keeps_altered_enums",
+		Mutate: func(s *Schema) {
+			s.Table("user", func(t *Table) {
+				t.Enum("status", Values("active", "banned"))
+			})
+			s.DropCreated()
+		},
+		PostgresResult: "CREATE TYPE \"user_status\" AS ENUM ('active', 'banned');ALTER TABLE \"user\" ADD COLUMN \"status\" \"user_status\" NOT NULL;",
+	},
+	{
+		Name: "referenced_tables_first",
+		Mutate: func(s *Schema) {
+			s.Create("comment", func(t *Table) {
+				t.Integer("user_id").References("user", "id")
+			})
+			s.Create("user", func(t *Table) {
+				t.Primary("id")
+			})
+			s.DropCreated()
+		},
+		SqliteResult:   "CREATE TABLE `user` ('id' INTEGER PRIMARY KEY);CREATE TABLE `comment` ('user_id' INTEGER NOT NULL, CONSTRAINT `fk_comment_user_id` FOREIGN KEY ('user_id') REFERENCES `user`('id'));PRAGMA defer_foreign_keys = ON;DROP TABLE `comment`;DROP TABLE `user`;",
+		PostgresResult: "CREATE TABLE \"user\" (\"id\" INTEGER PRIMARY KEY);CREATE TABLE \"comment\" (\"user_id\" INTEGER NOT NULL, CONSTRAINT \"fk_comment_user_id\" FOREIGN KEY (\"user_id\") REFERENCES \"user\"(\"id\"));DROP TABLE \"comment\", \"user\";",
+	},
+	{
+		Name: "reference_cycle",
+		Mutate: func(s *Schema) {
+			s.Create("user", func(t *Table) {
+				t.Primary("id")
+				t.Integer("avatar_id").Null().References("image", "id")
+			})
+			s.Create("image", func(t *Table) {
+				t.Primary("id")
+				t.Integer("user_id").References("user", "id")
+			})
+		},
+		MysqlResult: "CREATE TABLE `image` (`id` INTEGER PRIMARY KEY,\n`user_id` INTEGER NOT NULL);" +
+			"CREATE TABLE `user` (`id` INTEGER PRIMARY KEY,\n`avatar_id` INTEGER NULL, CONSTRAINT `fk_user_avatar_id` FOREIGN KEY (`avatar_id`) REFERENCES `image`(`id`));" +
+			"ALTER TABLE `image` ADD CONSTRAINT `fk_image_user_id` FOREIGN KEY (`user_id`) REFERENCES `user`(`id`);",
+	},
+	{
+		Name: "drop_tables",
+		Mutate: func(s *Schema) {
+			s.DropForeign("comment", "user_id")
+			s.Drop("user")
+			s.Drop("comment")
+		},
+		SqliteResult:   "-- rebuild `comment`\n-- DROP CONSTRAINT `fk_comment_user_id`;PRAGMA defer_foreign_keys = ON;DROP TABLE `user`;DROP TABLE `comment`;",
+		MysqlResult:    "ALTER TABLE `comment` DROP FOREIGN KEY `fk_comment_user_id`;DROP TABLE `user`, `comment`;",
+		PostgresResult: "ALTER TABLE \"comment\" DROP CONSTRAINT \"fk_comment_user_id\";DROP TABLE \"user\", \"comment\";",
+	},
+}
+
+func testSchema(t *testing.T, driverType driver.Type, result func(s testSchemaStatement) string) {
+	for i, s := range schemaStatements {
+		expected := result(s)
+		if expected == "" {
+			continue
+		}
+		t.Run(fmt.Sprintf("Schema '%s' - %d", s.Name, i), func(t *testing.T) {
+			schema := New(driverType, "test")
+			s.Mutate(schema)
+			statements, err := schema.Schema.Statements()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sql := strings.Join(statements, ";") + ";"
+			if !sqlStatementsAreEqual(expected, sql) {
+				t.Errorf("Expected \n%s\n but got \n%s\n", strings.TrimSpace(expected), strings.TrimSpace(sql))
+			}
+		})
+	}
+}
+
+func TestSqliteSchema(t *testing.T) {
+	testSchema(t, driver.TypeSqlite3, func(s testSchemaStatement) string { return s.SqliteResult })
+}
+
+func TestMysqlSchema(t *testing.T) {
+	testSchema(t, driver.TypeMysql, func(s testSchemaStatement) string { return s.MysqlResult })
+}
+
+func TestPostgresSchema(t *testing.T) {
+	testSchema(t, driver.TypePostgres, func(s testSchemaStatement) string { return s.PostgresResult })
+}
+
+func TestPostgresQuoting(t *testing.T) {
+	for _, s := range schemaStatements {
+		if s.PostgresResult == "" {
+			continue
+		}
+		schema := New(driver.TypePostgres, "test")
+		s.Mutate(schema)
+		statements, err := schema.Schema.Statements()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, statement := range statements {
+			if strings.Contains(statement, "`") {
+				t.Errorf("Expected postgres identifiers in '%s' to use double quotes but got %s", s.Name, statement)
+			}
+		}
+	}
+}
+
+func TestSqliteTrigger(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("user", func(t *Table) {
+			t.Primary("id")
+			t.String("name")
+		})
+		s.Create("audit", func(t *Table) {
+			t.Integer("user_id")
+		})
+		s.CreateTrigger("user_audit", "user", func(t *Trigger) {
+			t.After(TriggerInsert).Body(driver.TypeSqlite3, "INSERT INTO audit (user_id) VALUES (NEW.id)")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO user (name) VALUES ('a'), ('b')"); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM audit").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected trigger to insert 2 rows but got %d", count)
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.DropTrigger("user_audit", "user")
+	})
+	if _, err := db.Exec("INSERT INTO user (name) VALUES ('c')"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("SELECT count(*) FROM audit").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected trigger to be dropped but got %d rows", count)
+	}
+}
+
+func TestSqliteStrict(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("user", func(t *Table) {
+			t.Primary("id")
+			t.Integer("age")
+			t.Strict()
+		})
+	})
+	if _, err := db.Exec("INSERT INTO user (age) VALUES ('old')"); err == nil {
+		t.Error("Expected strict table to reject text in an integer column")
+	}
+}
+
+func TestSqliteDecimal(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("product", func(t *Table) {
+			t.Decimal("price", 20, 2)
+		})
+	})
+	if _, err := db.Exec("INSERT INTO product (price) VALUES ('10.00'), ('9.50'), ('100.25')"); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM product WHERE price > 5").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("Expected decimals to compare as numbers but got %d rows", count)
+	}
+	var max float64
+	if err := db.QueryRow("SELECT max(price) FROM product").Scan(&max); err != nil {
+		t.Fatal(err)
+	}
+	if max != 100.25 {
+		t.Errorf("Expected the largest decimal to be 100.25 but got %v", max)
+	}
+	rows, err := db.Query("SELECT price FROM product ORDER BY price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var prices []float64
+	for rows.Next() {
+		var price float64
+		if err := rows.Scan(&price); err != nil {
+			t.Fatal(err)
+		}
+		prices = append(prices, price)
+	}
+	if fmt.Sprint(prices) != "[9.5 10 100.25]" {
+		t.Errorf("Expected decimals to sort numerically but got %v", prices)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	up := New(driver.TypePostgres, "test")
+	up.CreateEnum("mood", "happy", "sad")
+	up.Create("user", func(t *Table) {
+		t.Primary("id")
+	})
+	up.Table("post", func(t *Table) {
+		t.Column("title").Name("headline")
+		t.Integer("user_id").References("user", "id")
+		t.Index("headline").Name("idx_headline")
+		t.Check("user_id > 0").Name("chk_user")
+	})
+	up.CreateView("active_user", "SELECT * FROM user")
+	down, err := up.Schema.Reverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := down.Schema.Statements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "DROP VIEW \"active_user\";DROP INDEX \"idx_headline\";" +
+		"ALTER TABLE \"post\" DROP CONSTRAINT \"fk_post_user_id\";" +
+		"ALTER TABLE \"post\" RENAME COLUMN \"headline\" TO \"title\";" +
+		"ALTER TABLE \"post\" DROP CONSTRAINT \"chk_user\";" +
+		"ALTER TABLE \"post\" DROP COLUMN \"user_id\";" +
+		"DROP TABLE \"user\";DROP TYPE \"mood\";"
+	sql := strings.Join(statements, ";") + ";"
+	if !sqlStatementsAreEqual(expected, sql) {
+		t.Errorf("Expected \n%s\n but got \n%s\n", expected, sql)
+	}
+
+	up = New(driver.TypeSqlite3, "test")
+	up.Drop("user")
+	up.Exec("DELETE FROM post")
+	if _, err = up.Schema.Reverse(); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Expected dropping a table to be irreversible but got %v", err)
+	}
+
+	up = New(driver.TypePostgres, "test")
+	up.CreateExtension("citext")
+	if _, err = up.Schema.Reverse(); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Expected creating an extension to be irreversible but got %v", err)
+	}
+
+	up = New(driver.TypeMysql, "test")
+	up.Table("user", func(t *Table) {
+		t.String("name").Collate(driver.TypeMysql, "utf8mb4_bin").Modify()
+	})
+	if _, err = up.Schema.Reverse(); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Expected modifying a column to be irreversible but got %v", err)
+	}
+}
+
+func TestSqliteDropReferencedTables(t *testing.T) {
+	db := openSqlite(t)
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatal(err)
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("comment", func(t *Table) {
+			t.Integer("user_id").References("user", "id")
+		})
+		s.Create("user", func(t *Table) {
+			t.Primary("id")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO user (id) VALUES (1); INSERT INTO comment (user_id) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Drop("user")
+		s.Drop("comment")
+	})
+}
+
+func TestSqliteExecParams(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("role", func(t *Table) {
+			t.String("name")
+		})
+		s.Exec("INSERT INTO role (name) VALUES (?)", "admin")
+	})
+	var name string
+	if err := db.QueryRow("SELECT name FROM role").Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "admin" {
+		t.Errorf("Expected exec to insert admin but got %s", name)
+	}
+}
+
+func TestSqliteExecByDriver(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("role", func(t *Table) {
+			t.String("name")
+		})
+		s.ExecByDriver(map[driver.Type]string{
+			driver.TypeSqlite3:  "INSERT INTO role (name) VALUES (?), (?)",
+			driver.TypePostgres: "INSERT INTO role (name) VALUES ($1), ($2)",
+		}, "admin", "guest")
+	})
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM role").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected exec to insert 2 roles but got %d", count)
+	}
+}
+
+func TestExecMissingDriver(t *testing.T) {
+	s := New(driver.TypeMysql, "test")
+	s.ExecByDriver(map[driver.Type]string{
+		driver.TypePostgres: "INSERT INTO role (name) VALUES ($1)",
+	}, "admin")
+	if _, err := s.Schema.Statements(); err == nil {
+		t.Error("Expected an error for a driver without a statement")
+	}
+}
+
+func TestUnsupportedSequences(t *testing.T) {
+	for _, driverType := range []driver.Type{driver.TypeSqlite3, driver.TypeMysql} {
+		s := New(driverType, "test")
+		s.CreateSequence("invoice_number", nil)
+		if _, err := s.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Expected sequences to be unsupported by %s but got %v", driverType, err)
+		}
+	}
+}
+
+func TestUnsupportedCustomTypes(t *testing.T) {
+	for _, driverType := range []driver.Type{driver.TypeSqlite3, driver.TypeMysql} {
+		s := New(driverType, "test")
+		s.CreateExtension("citext")
+		s.CreateDomain("positive_int", "INTEGER", nil)
+		if _, err := s.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Expected custom types to be unsupported by %s but got %v", driverType, err)
+		}
+		s = New(driverType, "test")
+		s.Create("customer", func(t *Table) {
+			t.Column("email").CustomType("citext")
+		})
+		if _, err := s.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Expected custom column types to be unsupported by %s but got %v", driverType, err)
+		}
+	}
+}
+
+func TestUnsupportedPartitions(t *testing.T) {
+	s := New(driver.TypeSqlite3, "test")
+	s.Create("event", func(t *Table) {
+		t.Column("created_at", Date())
+		t.PartitionBy(PartitionRange, "created_at")
+	})
+	if _, err := s.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected partitioned tables to be unsupported by SQLite but got %v", err)
+	}
+	s = New(driver.TypeMysql, "test")
+	s.DetachPartition("event", "event_2024")
+	if _, err := s.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected detaching partitions to be unsupported by MySQL but got %v", err)
+	}
+}
+
+func TestPartitionErrors(t *testing.T) {
+	event := func(method PartitionMethod, fn PartitionMutator) func(s *Schema) {
+		return func(s *Schema) {
+			s.Create("event", func(t *Table) {
+				t.Integer("kind")
+				t.PartitionBy(method, "kind")
+				t.Partition("event_a", fn)
+			})
+		}
+	}
+	cases := []struct {
+		Name   string
+		Driver driver.Type
+		Mutate func(s *Schema)
+	}{
+		{"mysql default list partition", driver.TypeMysql, event(PartitionList, func(p *Partition) { p.Default() })},
+		{"hash default partition", driver.TypePostgres, event(PartitionHash, func(p *Partition) { p.Default() })},
+		{"range bound on list partition", driver.TypePostgres, event(PartitionList, func(p *Partition) { p.Range(1, 2) })},
+		{"list bound on range partition", driver.TypeMysql, event(PartitionRange, func(p *Partition) { p.List(1, 2) })},
+		{"partition without bound", driver.TypePostgres, event(PartitionRange, nil)},
+		{"partition with two bounds", driver.TypePostgres, event(PartitionList, func(p *Partition) { p.List(1).Default() })},
+		{"remainder not less than modulus", driver.TypePostgres, event(PartitionHash, func(p *Partition) { p.Hash(2, 2) })},
+		{"created partition without bound", driver.TypePostgres, func(s *Schema) {
+			s.CreatePartition("event", "event_b", nil)
+		}},
+		{"attached partition without bound", driver.TypePostgres, func(s *Schema) {
+			s.AttachPartition("event", "event_b", nil)
+		}},
+	}
+	for _, c := range cases {
+		s := New(c.Driver, "test")
+		c.Mutate(s)
+		if _, err := s.Schema.Statements(); err == nil {
+			t.Errorf("Expected %s to fail", c.Name)
+		}
+	}
+	s := New(driver.TypeMysql, "test")
+	event(PartitionHash, nil)(s)
+	s.CreatePartition("event", "event_b", nil)
+	if _, err := s.Schema.Statements(); err != nil {
+		t.Errorf("Expected MySQL hash partitions without a bound to be valid but got %v", err)
+	}
+}
+
+func TestReverseSecurity(t *testing.T) {
+	s := New(driver.TypePostgres, "test")
+	s.Table("invoice", func(t *Table) {
+		t.EnableRowSecurity()
+		t.ForceRowSecurity()
+		t.Policy("tenant_isolation").Using("tenant_id = 1")
+	})
+	s.Grant("invoice", "app", "SELECT")
+	down, err := s.Schema.Reverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := down.Schema.Statements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`REVOKE SELECT ON TABLE "invoice" FROM "app"`,
+		`DROP POLICY "tenant_isolation" ON "invoice"`,
+		`ALTER TABLE "invoice" NO FORCE ROW LEVEL SECURITY`,
+		`ALTER TABLE "invoice" DISABLE ROW LEVEL SECURITY`,
+	}
+	if fmt.Sprint(statements) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v but got %v", expected, statements)
+	}
+	s = New(driver.TypePostgres, "test")
+	s.Table("invoice", func(t *Table) {
+		t.NoForceRowSecurity()
+	})
+	if _, err := s.Schema.Reverse(); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Expected no longer forcing row security to be irreversible but got %v", err)
+	}
+	s = New(driver.TypePostgres, "test")
+	s.Revoke("invoice", "app", "DELETE")
+	if _, err := s.Schema.Reverse(); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Expected revoking privileges to be irreversible but got %v", err)
+	}
+	s = New(driver.TypeSqlite3, "test")
+	s.Grant("invoice", "app")
+	if _, err := s.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected grants to be unsupported by SQLite but got %v", err)
+	}
+}
+
+func TestSqliteCollation(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("user", func(t *Table) {
+			t.String("username").Collate(driver.TypeSqlite3, "NOCASE").Unique()
+		})
+	})
+	if _, err := db.Exec("INSERT INTO user (username) VALUES ('Alice')"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO user (username) VALUES ('alice')"); err == nil {
+		t.Error("Expected usernames to be unique regardless of case")
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("member", func(t *Table) {
+			t.String("name").Index("idx_member_name")
+		})
+		s.Exec("INSERT INTO member (name) VALUES ('Bob')")
+		s.Table("member", func(t *Table) {
+			t.String("name").Collate(driver.TypeSqlite3, "NOCASE").Modify()
+		})
+	})
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM member WHERE name = 'bob'").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Error("Expected the modified column to compare names regardless of case")
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_index_list('member')").Scan(&count); err != nil || count != 1 {
+		t.Errorf("Expected the index to be kept but got %d, %v", count, err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	build := func(column string) *Schema {
+		s := New(driver.TypeSqlite3, "test")
+		s.Create("role", func(t *Table) {
+			t.String(column)
+		})
+		s.Exec("INSERT INTO role (name) VALUES (?)", "admin")
+		return s
+	}
+	s := build("name")
+	hash, err := s.Schema.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(hash), "v1:") {
+		t.Errorf("Expected the hash to record its version but got %s", hash)
+	}
+	if ok, err := s.Schema.VerifyHash(hash); err != nil || !ok {
+		t.Errorf("Expected %s to verify but got %v, %v", hash, ok, err)
+	}
+	if ok, _ := build("title").Schema.VerifyHash(hash); ok {
+		t.Error("Expected a different schema not to verify")
+	}
+	// unversioned MD5 hashes were made before hashes were versioned
+	legacy := make([]byte, md5.Size)
+	if ok, err := s.Schema.VerifyHash(legacy); !errors.Is(err, ErrLegacyHash) || ok {
+		t.Errorf("Expected legacy hashes to be unverifiable but got %v, %v", ok, err)
+	}
+	if _, err := s.Schema.VerifyHash([]byte("v99:abc")); err == nil {
+		t.Error("Expected an error for an unknown hash version")
+	}
+	for _, h := range []string{"vault:abc", "v1", "abc"} {
+		if _, err := s.Schema.VerifyHash([]byte(h)); err == nil {
+			t.Errorf("Expected an error for the unrecognized hash %q", h)
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	s := New(driver.TypeSqlite3, "test")
+	s.Create("role", func(t *Table) {
+		t.String("name")
+	})
+	s.Exec("INSERT INTO role (name) VALUES ('admin')")
+	s.DropIndex("idx_old")
+	kinds := []OpKind{}
+	for _, op := range s.Schema.Operations {
+		kinds = append(kinds, op.Kind)
+	}
+	if fmt.Sprint(kinds) != fmt.Sprint([]OpKind{OpCreateTable, OpExec, OpDropIndex}) {
+		t.Errorf("Expected operations in call order but got %v", kinds)
+	}
+	// operations can be transformed before rendering
+	ops := s.Schema.Operations
+	s.Schema.Operations = []Operation{ops[2], ops[0], ops[1]}
+	statements, err := s.Schema.Statements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statements[0] != "DROP INDEX `idx_old`" {
+		t.Errorf("Expected reordered operations to render in their new order but got %v", statements)
+	}
+}
@@ -0,0 +1,119 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"text/template"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+// searchDef is a full-text search index over text columns. SQLite stores it in an FTS5 virtual table kept in sync with
+// the table by triggers, MySQL uses a FULLTEXT index and Postgres indexes a generated tsvector column with GIN.
+type searchDef struct {
+	Table    *TableDef
+	Name     string
+	Columns  []string
+	Language string
+}
+
+// GetLanguage is the Postgres text search configuration, which defaults to english
+func (s *searchDef) GetLanguage() string {
+	if s.Language == "" {
+		return "english"
+	}
+	return s.Language
+}
+
+// Triggers are the SQLite triggers copying changes of the table into the FTS5 table
+func (s *searchDef) Triggers() []*searchTrigger {
+	return []*searchTrigger{
+		{Search: s, Name: s.Name + "_ai", Event: TriggerInsert},
+		{Search: s, Name: s.Name + "_ad", Event: TriggerDelete},
+		{Search: s, Name: s.Name + "_au", Event: TriggerUpdate},
+	}
+}
+
+// statements renders the search index. Each driver only defines the templates it needs.
+func (s *searchDef) statements(tmp *template.Template) (statements []Statement, err error) {
+	if s.Table.Schema.Driver == driver.TypeSqlite3 {
+		statements = append(statements, Statement{Sql: "-- check fts5", run: s.checkFTS5})
+	}
+	if statements, err = s.Table.Schema.appendStatement(statements, tmp, "add_search_column", s); err != nil {
+		return
+	}
+	if statements, err = s.Table.Schema.appendStatement(statements, tmp, "create_search", s); err != nil {
+		return
+	}
+	for _, trigger := range s.Triggers() {
+		if statements, err = s.Table.Schema.appendStatement(statements, tmp, "search_trigger", trigger); err != nil {
+			return
+		}
+	}
+	// external content tables start out empty, so rows that already exist are indexed too
+	statements, err = s.Table.Schema.appendStatement(statements, tmp, "rebuild_search", s)
+	return
+}
+
+func (s *searchDef) dropStatements(tmp *template.Template) (statements []Statement, err error) {
+	for _, trigger := range s.Triggers() {
+		if statements, err = s.Table.Schema.appendStatement(statements, tmp, "drop_search_trigger", trigger); err != nil {
+			return
+		}
+	}
+	statements, err = s.Table.Schema.appendStatement(statements, tmp, "drop_search", s)
+	return
+}
+
+// checkFTS5 returns ErrNoFTS5 instead of letting SQLite fail with "no such module: fts5"
+func (s *searchDef) checkFTS5(tx *sql.Tx) (err error) {
+	var count int
+	if err = tx.QueryRow("SELECT COUNT(*) FROM pragma_module_list WHERE name = 'fts5'").Scan(&count); err != nil {
+		return
+	}
+	if count == 0 {
+		return fmt.Errorf("search %s: %w", s.Name, ErrNoFTS5)
+	}
+	return
+}
+
+func (s *searchDef) validate() (errs []error) {
+	if len(s.Columns) == 0 {
+		errs = append(errs, fmt.Errorf("search %s has no columns", s.Name))
+	}
+	if !s.Table.WillCreate {
+		return
+	}
+	for _, col := range s.Columns {
+		if s.Table.column(col) == nil {
+			errs = append(errs, fmt.Errorf("search %s uses unknown column %s", s.Name, col))
+		}
+	}
+	return
+}
+
+type searchTrigger struct {
+	Search *searchDef
+	Name   string
+	Event  TriggerEvent
+}
+
+// Old reports whether the trigger removes the old row from the FTS5 table
+func (t *searchTrigger) Old() bool {
+	return t.Event != TriggerInsert
+}
+
+// New reports whether the trigger adds the new row to the FTS5 table
+func (t *searchTrigger) New() bool {
+	return t.Event != TriggerDelete
+}
+
+type searchBuilder struct {
+	search *searchDef
+}
+
+// Language sets the Postgres text search configuration used to parse the columns, like english or simple
+func (s *searchBuilder) Language(language string) *searchBuilder {
+	s.search.Language = language
+	return s
+}
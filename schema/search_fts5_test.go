@@ -0,0 +1,43 @@
+//go:build sqlite_fts5
+
+package schema
+
+import "testing"
+
+func TestSqliteSearch(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("post", func(t *Table) {
+			t.Primary("id")
+			t.String("title")
+			t.Text("body")
+		})
+		s.Exec("INSERT INTO post (title, body) VALUES ('first', 'hello world')")
+		s.Table("post", func(t *Table) {
+			t.Search("post_search", "title", "body")
+		})
+	})
+	search := func(term string) (count int) {
+		if err := db.QueryRow("SELECT COUNT(*) FROM post_search WHERE post_search MATCH ?", term).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if search("hello") != 1 {
+		t.Error("Expected existing rows to be indexed")
+	}
+	if _, err := db.Exec("UPDATE post SET body = 'goodbye world'"); err != nil {
+		t.Fatal(err)
+	}
+	if search("hello") != 0 || search("goodbye") != 1 {
+		t.Error("Expected updates to be indexed")
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Table("post", func(t *Table) {
+			t.DropSearch("post_search")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO post (title, body) VALUES ('second', 'hi')"); err != nil {
+		t.Errorf("Expected the search triggers to be dropped: %s", err)
+	}
+}
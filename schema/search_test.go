@@ -0,0 +1,28 @@
+//go:build !sqlite_fts5
+
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+func TestSqliteSearchWithoutFTS5(t *testing.T) {
+	db := openSqlite(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	s := New(driver.TypeSqlite3, "test")
+	s.Create("post", func(t *Table) {
+		t.Primary("id")
+		t.Text("body")
+		t.Search("post_search", "body")
+	})
+	if err = s.Schema.Run(tx, nil); !errors.Is(err, ErrNoFTS5) {
+		t.Errorf("Expected %v without the sqlite_fts5 build tag but got %v", ErrNoFTS5, err)
+	}
+}
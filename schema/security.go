@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+type PolicyCommand string
+
+const (
+	PolicyAll    PolicyCommand = "ALL"
+	PolicySelect PolicyCommand = "SELECT"
+	PolicyInsert PolicyCommand = "INSERT"
+	PolicyUpdate PolicyCommand = "UPDATE"
+	PolicyDelete PolicyCommand = "DELETE"
+)
+
+// policyDef is a Postgres row level security policy. Rows are visible when the Using expression is true and can be
+// written when the WithCheck expression is true for the new row.
+type policyDef struct {
+	Table       *TableDef
+	Name        string
+	Command     PolicyCommand
+	Roles       []string
+	Using       string
+	WithCheck   string
+	Restrictive bool
+}
+
+type policyBuilder struct {
+	policy *policyDef
+}
+
+// For limits the policy to one command. Policies apply to every command by default.
+func (p *policyBuilder) For(command PolicyCommand) *policyBuilder {
+	p.policy.Command = command
+	return p
+}
+
+// To limits the policy to the given roles. Policies apply to every role by default.
+func (p *policyBuilder) To(roles ...string) *policyBuilder {
+	p.policy.Roles = append(p.policy.Roles, roles...)
+	return p
+}
+
+func (p *policyBuilder) Using(expr string) *policyBuilder {
+	p.policy.Using = expr
+	return p
+}
+
+func (p *policyBuilder) WithCheck(expr string) *policyBuilder {
+	p.policy.WithCheck = expr
+	return p
+}
+
+// Restrictive policies must pass along with the permissive ones instead of being one of the alternatives
+func (p *policyBuilder) Restrictive() *policyBuilder {
+	p.policy.Restrictive = true
+	return p
+}
+
+// securityStatements drops policies, toggles row level security and creates policies, in that order
+func (t *TableDef) securityStatements(tmp *template.Template) (statements []Statement, err error) {
+	for _, name := range t.DroppingPolicies {
+		if statements, err = t.Schema.appendStatement(statements, tmp, "drop_policy", &policyDef{Table: t, Name: name}); err != nil {
+			return
+		}
+	}
+	if t.EnableRowSecurity {
+		if statements, err = t.Schema.appendStatement(statements, tmp, "enable_row_security", t); err != nil {
+			return
+		}
+	}
+	if t.ForceRowSecurity {
+		if statements, err = t.Schema.appendStatement(statements, tmp, "force_row_security", t); err != nil {
+			return
+		}
+	}
+	if t.NoForceRowSecurity {
+		if statements, err = t.Schema.appendStatement(statements, tmp, "no_force_row_security", t); err != nil {
+			return
+		}
+	}
+	if t.DisableRowSecurity {
+		if statements, err = t.Schema.appendStatement(statements, tmp, "disable_row_security", t); err != nil {
+			return
+		}
+	}
+	for _, p := range t.Policies {
+		if statements, err = t.Schema.appendStatement(statements, tmp, "create_policy", p); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// roleKeywords stand for a role instead of naming one, like PUBLIC for every role, so they aren't quoted
+var roleKeywords = map[driver.Type][]string{
+	driver.TypePostgres: {"PUBLIC", "CURRENT_USER", "CURRENT_ROLE", "SESSION_USER"},
+	driver.TypeMysql:    {"CURRENT_USER"},
+}
+
+func quoteRole(driverType driver.Type, role string) string {
+	for _, keyword := range roleKeywords[driverType] {
+		if strings.EqualFold(role, keyword) {
+			return keyword
+		}
+	}
+	if driverType == driver.TypePostgres {
+		return fmt.Sprintf(`"%s"`, role)
+	}
+	return quoteString(driverType, role)
+}
+
+type GrantObject string
+
+const (
+	GrantTable    GrantObject = "TABLE"
+	GrantSequence GrantObject = "SEQUENCE"
+)
+
+// GrantDef gives privileges on a table or sequence to a role or takes them away
+type GrantDef struct {
+	Schema     *SchemaDef
+	Object     GrantObject
+	Name       string
+	Role       string
+	Privileges []string
+}
+
+// GetPrivileges lists the privileges, defaulting to all of them
+func (g *GrantDef) GetPrivileges() string {
+	if len(g.Privileges) == 0 {
+		return "ALL PRIVILEGES"
+	}
+	return strings.Join(g.Privileges, ", ")
+}
@@ -0,0 +1,57 @@
+package schema
+
+// SequenceDef is a standalone Postgres sequence. Sequences aren't supported by SQLite or MySQL.
+type SequenceDef struct {
+	Schema    *SchemaDef
+	Name      string
+	Start     *int64
+	Increment *int64
+	MinValue  *int64
+	MaxValue  *int64
+	Cycle     bool
+	// OwnedTable and OwnedColumn are the column the sequence is dropped with
+	OwnedTable  string
+	OwnedColumn string
+	// Restart is the next value of a restarted sequence
+	Restart int64
+}
+
+type SequenceMutator func(s *Sequence)
+
+type Sequence struct {
+	sequenceDef *SequenceDef
+}
+
+// Start sets the first value of the sequence
+func (s *Sequence) Start(value int64) *Sequence {
+	s.sequenceDef.Start = &value
+	return s
+}
+
+func (s *Sequence) Increment(value int64) *Sequence {
+	s.sequenceDef.Increment = &value
+	return s
+}
+
+func (s *Sequence) Min(value int64) *Sequence {
+	s.sequenceDef.MinValue = &value
+	return s
+}
+
+func (s *Sequence) Max(value int64) *Sequence {
+	s.sequenceDef.MaxValue = &value
+	return s
+}
+
+// Cycle wraps the sequence around to its minimum value after it reaches the maximum instead of failing
+func (s *Sequence) Cycle() *Sequence {
+	s.sequenceDef.Cycle = true
+	return s
+}
+
+// OwnedBy ties the sequence to a column so it's dropped together with the column or its table
+func (s *Sequence) OwnedBy(table, column string) *Sequence {
+	s.sequenceDef.OwnedTable = table
+	s.sequenceDef.OwnedColumn = column
+	return s
+}
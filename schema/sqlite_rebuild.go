@@ -0,0 +1,376 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// sqliteRebuild describes table changes that SQLite's ALTER TABLE can't make, like adding or dropping constraints or
+// changing the definition of a column. They are applied by recreating the table from its stored CREATE TABLE statement,
+// copying the rows over and recreating the indices and triggers of the table and the views of the schema.
+// See https://www.sqlite.org/lang_altertable.html#otheralter.
+//
+// SQLite applies the ON DELETE actions of referencing tables when the old table is dropped if foreign_keys is enabled,
+// so the rebuild fails with ErrForeignKeysEnabled unless enforcement is off. PRAGMA foreign_keys has no effect inside a
+// transaction, so it has to be turned off on the connection before the migration begins.
+type sqliteRebuild struct {
+	Table           string
+	AddConstraints  []string
+	DropConstraints []string
+	// ModifyColumns are new column definitions replacing the definitions of the columns with the same name
+	ModifyColumns []string
+}
+
+func (r *sqliteRebuild) statement() Statement {
+	return Statement{Sql: r.String(), run: r.run}
+}
+
+// String describes the rebuild using SQL comments so it can be logged and hashed like any other statement
+func (r *sqliteRebuild) String() string {
+	lines := []string{fmt.Sprintf("-- rebuild `%s`", r.Table)}
+	for _, name := range r.DropConstraints {
+		lines = append(lines, fmt.Sprintf("-- DROP CONSTRAINT `%s`", name))
+	}
+	for _, constraint := range r.AddConstraints {
+		lines = append(lines, "-- ADD "+strings.Join(strings.Fields(constraint), " "))
+	}
+	for _, column := range r.ModifyColumns {
+		lines = append(lines, "-- MODIFY COLUMN "+strings.Join(strings.Fields(column), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *sqliteRebuild) run(tx *sql.Tx) (err error) {
+	var foreignKeys bool
+	if err = tx.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		return
+	}
+	if foreignKeys {
+		return fmt.Errorf("can't rebuild table %s: %w, run PRAGMA foreign_keys = OFF before the migration begins", r.Table, ErrForeignKeysEnabled)
+	}
+	var createSql string
+	err = tx.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", r.Table).Scan(&createSql)
+	if err != nil {
+		return fmt.Errorf("failed to read definition of table %s: %w", r.Table, err)
+	}
+	def, err := parseSqliteCreateTable(createSql)
+	if err != nil {
+		return
+	}
+	for _, name := range r.DropConstraints {
+		if err = def.dropConstraint(name); err != nil {
+			return
+		}
+	}
+	def.Defs = append(def.Defs, r.AddConstraints...)
+	for _, column := range r.ModifyColumns {
+		if err = def.replaceColumn(column); err != nil {
+			return
+		}
+	}
+
+	// renaming the new table fails while a view refers to the old one, so every view is dropped and recreated in the
+	// order they were created along with their triggers
+	views, err := sqliteQueryStrings(tx, "SELECT name FROM sqlite_master WHERE type = 'view' ORDER BY rowid")
+	if err != nil {
+		return
+	}
+	viewSql, err := sqliteQueryStrings(tx, "SELECT sql FROM sqlite_master WHERE type = 'view' ORDER BY rowid")
+	if err != nil {
+		return
+	}
+	dependents, err := sqliteQueryStrings(tx, "SELECT sql FROM sqlite_master WHERE type IN ('index', 'trigger') AND (tbl_name = ? OR tbl_name IN (SELECT name FROM sqlite_master WHERE type = 'view')) AND sql IS NOT NULL ORDER BY type", r.Table)
+	if err != nil {
+		return
+	}
+	for _, view := range views {
+		if _, err = tx.Exec(fmt.Sprintf("DROP VIEW %s", sqliteQuote(view))); err != nil {
+			return
+		}
+	}
+	tmpName := "_zee_rebuild_" + r.Table
+	if _, err = tx.Exec(def.sql(tmpName)); err != nil {
+		return
+	}
+	columns, err := sqliteSharedColumns(tx, r.Table, tmpName)
+	if err != nil {
+		return
+	}
+	statements := []string{
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", sqliteQuote(tmpName), columns, columns, sqliteQuote(r.Table)),
+		fmt.Sprintf("DROP TABLE %s", sqliteQuote(r.Table)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", sqliteQuote(tmpName), sqliteQuote(r.Table)),
+	}
+	statements = append(statements, viewSql...)
+	for _, statement := range append(statements, dependents...) {
+		if _, err = tx.Exec(statement); err != nil {
+			return
+		}
+	}
+	// foreign keys aren't enforced during the rebuild, so rows copied into the table are checked afterwards
+	violations, err := sqliteQueryStrings(tx, "SELECT `table` FROM pragma_foreign_key_check(?)", r.Table)
+	if err != nil {
+		return
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("rebuilt table %s has %d rows violating its foreign keys", r.Table, len(violations))
+	}
+	return
+}
+
+// sqliteSharedColumns returns the quoted list of stored columns that exist in both tables
+func sqliteSharedColumns(tx *sql.Tx, from, to string) (columns string, err error) {
+	q := "SELECT name FROM pragma_table_xinfo(?) WHERE hidden = 0"
+	fromColumns, err := sqliteQueryStrings(tx, q, from)
+	if err != nil {
+		return
+	}
+	toColumns, err := sqliteQueryStrings(tx, q, to)
+	if err != nil {
+		return
+	}
+	shared := []string{}
+	for _, c := range fromColumns {
+		for _, c2 := range toColumns {
+			if c == c2 {
+				shared = append(shared, sqliteQuote(c))
+				break
+			}
+		}
+	}
+	return strings.Join(shared, ", "), nil
+}
+
+func sqliteQueryStrings(tx *sql.Tx, q string, params ...interface{}) (res []string, err error) {
+	rows, err := tx.Query(q, params...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s string
+		if err = rows.Scan(&s); err != nil {
+			return
+		}
+		res = append(res, s)
+	}
+	err = rows.Err()
+	return
+}
+
+func sqliteQuote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func sqliteUnquote(name string) string {
+	if len(name) < 2 {
+		return name
+	}
+	switch q := name[0]; q {
+	case '\'', '"', '`':
+		if name[len(name)-1] == q {
+			return strings.ReplaceAll(name[1:len(name)-1], string([]byte{q, q}), string(q))
+		}
+	case '[':
+		if name[len(name)-1] == ']' {
+			return name[1 : len(name)-1]
+		}
+	}
+	return name
+}
+
+// sqliteCreateTable is a CREATE TABLE statement split into its column definitions and table constraints
+type sqliteCreateTable struct {
+	Defs []string
+	// Suffix is everything after the closing parenthesis, like WITHOUT ROWID
+	Suffix string
+}
+
+func (d *sqliteCreateTable) sql(name string) string {
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)%s", sqliteQuote(name), strings.Join(d.Defs, ",\n"), d.Suffix)
+}
+
+// dropConstraint removes a named table constraint or a named check constraint that is part of a column definition
+func (d *sqliteCreateTable) dropConstraint(name string) error {
+	for i, def := range d.Defs {
+		tokens := sqliteTokens(def)
+		for j := 0; j+1 < len(tokens); j++ {
+			if !strings.EqualFold(tokens[j].Text, "CONSTRAINT") || sqliteUnquote(tokens[j+1].Text) != name {
+				continue
+			}
+			if j == 0 {
+				d.Defs = append(d.Defs[:i], d.Defs[i+1:]...)
+				return nil
+			}
+			if j+3 < len(tokens) && strings.EqualFold(tokens[j+2].Text, "CHECK") && strings.HasPrefix(tokens[j+3].Text, "(") {
+				d.Defs[i] = strings.TrimRight(def[:tokens[j].Start], " ") + def[tokens[j+3].End:]
+				return nil
+			}
+			return fmt.Errorf("constraint %s can't be dropped from its column definition", name)
+		}
+	}
+	return fmt.Errorf("constraint %s does not exist", name)
+}
+
+// replaceColumn replaces the definition of the column named by the first token of column
+func (d *sqliteCreateTable) replaceColumn(column string) error {
+	tokens := sqliteTokens(column)
+	if len(tokens) == 0 {
+		return fmt.Errorf("invalid column definition %q", column)
+	}
+	name := sqliteUnquote(tokens[0].Text)
+	for i, def := range d.Defs {
+		if defTokens := sqliteTokens(def); len(defTokens) > 0 && strings.EqualFold(sqliteUnquote(defTokens[0].Text), name) {
+			d.Defs[i] = column
+			return nil
+		}
+	}
+	return fmt.Errorf("column %s does not exist", name)
+}
+
+// parseSqliteCreateTable splits the body of a CREATE TABLE statement on top level commas. Quoted names, string
+// literals, comments and parenthesized expressions are skipped over.
+func parseSqliteCreateTable(createSql string) (def *sqliteCreateTable, err error) {
+	def = &sqliteCreateTable{}
+	depth, start := 0, 0
+	// a definition ending with a line comment needs to keep its newline or the comment swallows the next comma
+	commentTail := false
+	addDef := func(end int) {
+		d := strings.TrimSpace(createSql[start:end])
+		if commentTail {
+			d += "\n"
+		}
+		def.Defs = append(def.Defs, d)
+	}
+	for i := 0; i < len(createSql); i++ {
+		c := createSql[i]
+		if strings.HasPrefix(createSql[i:], "--") {
+			end := strings.IndexByte(createSql[i:], '\n')
+			if end < 0 {
+				break
+			}
+			i += end
+			commentTail = true
+			continue
+		}
+		switch c {
+		case '\'', '"', '`', '[':
+			i = skipQuoted(createSql, i)
+		case '/':
+			if strings.HasPrefix(createSql[i:], "/*") {
+				end := strings.Index(createSql[i:], "*/")
+				if end < 0 {
+					i = len(createSql)
+				} else {
+					i += end + 1
+				}
+			}
+		case '(':
+			depth++
+			if depth == 1 {
+				start = i + 1
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				addDef(i)
+				def.Suffix = createSql[i+1:]
+				return
+			}
+		case ',':
+			if depth == 1 {
+				addDef(i)
+				start = i + 1
+			}
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			commentTail = false
+		}
+	}
+	return nil, fmt.Errorf("unable to parse table definition: %s", createSql)
+}
+
+// skipQuoted returns the index of the quote closing the one at i. Doubled quotes are treated as escapes.
+func skipQuoted(s string, i int) int {
+	closing := s[i]
+	if closing == '[' {
+		closing = ']'
+	}
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != closing {
+			continue
+		}
+		if closing != ']' && j+1 < len(s) && s[j+1] == closing {
+			j++
+			continue
+		}
+		return j
+	}
+	return len(s)
+}
+
+// skipParens returns the index of the parenthesis closing the one at i
+func skipParens(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\'', '"', '`', '[':
+			j = skipQuoted(s, j)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(s)
+}
+
+type sqlToken struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// sqliteTokens splits a definition into words, quoted names or literals and parenthesized groups. Comments are
+// skipped.
+func sqliteTokens(def string) (tokens []sqlToken) {
+	for i := 0; i < len(def); i++ {
+		start := i
+		switch c := def[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case strings.HasPrefix(def[i:], "--"):
+			end := strings.IndexByte(def[i:], '\n')
+			if end < 0 {
+				return
+			}
+			i += end
+			continue
+		case strings.HasPrefix(def[i:], "/*"):
+			end := strings.Index(def[i:], "*/")
+			if end < 0 {
+				return
+			}
+			i += end + 1
+			continue
+		case strings.IndexByte("'\"`[", c) >= 0:
+			i = skipQuoted(def, i)
+		case c == '(':
+			i = skipParens(def, i)
+		default:
+			for i+1 < len(def) && strings.IndexByte(" \t\r\n()'\"`[", def[i+1]) < 0 {
+				i++
+			}
+		}
+		end := i + 1
+		if end > len(def) {
+			end = len(def)
+		}
+		tokens = append(tokens, sqlToken{Text: def[start:end], Start: start, End: end})
+	}
+	return
+}
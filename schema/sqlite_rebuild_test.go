@@ -0,0 +1,234 @@
+package schema
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wyattis/zee/isql/driver"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestParseSqliteCreateTable(t *testing.T) {
+	def, err := parseSqliteCreateTable("CREATE TABLE `a(b` (\n'id' INTEGER, -- the id, (really)\n'name' TEXT DEFAULT 'x,y',\nCHECK (length(name) > 0),\nCONSTRAINT `fk` FOREIGN KEY ('id') REFERENCES `b`('id')\n) WITHOUT ROWID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"'id' INTEGER", "-- the id, (really)\n'name' TEXT DEFAULT 'x,y'", "CHECK (length(name) > 0)", "CONSTRAINT `fk` FOREIGN KEY ('id') REFERENCES `b`('id')"}
+	if strings.Join(def.Defs, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q but got %q", expected, def.Defs)
+	}
+	if def.Suffix != " WITHOUT ROWID" {
+		t.Errorf("Expected suffix ' WITHOUT ROWID' but got %q", def.Suffix)
+	}
+	if err = def.dropConstraint("fk"); err != nil {
+		t.Error(err)
+	}
+	if len(def.Defs) != 3 {
+		t.Errorf("Expected constraint to be dropped but got %q", def.Defs)
+	}
+}
+
+func openSqlite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func runSqlite(t *testing.T, db *sql.DB, fn func(s *Schema)) {
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	s := New(driver.TypeSqlite3, "test")
+	fn(s)
+	if err = s.Schema.Run(tx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSqliteRebuild(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("membership", func(t *Table) {
+			t.Integer("user_id").Primary()
+			t.Integer("study_id").Primary()
+		})
+		s.Create("visit", func(t *Table) {
+			t.Primary("id").Autoincrement()
+			t.Integer("user_id")
+			t.Integer("study_id")
+			t.Integer("total").Generated("user_id + study_id").Stored()
+			t.Index("user_id").Name("idx_visit_user_id")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO membership (user_id, study_id) VALUES (1, 2), (3, 4); INSERT INTO visit (user_id, study_id) VALUES (1, 2), (3, 4)"); err != nil {
+		t.Fatal(err)
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Table("visit", func(t *Table) {
+			t.Foreign("user_id", "study_id").References("membership", "user_id", "study_id").OnDelete(CASCADE{})
+		})
+	})
+
+	var createSql string
+	if err := db.QueryRow("SELECT sql FROM sqlite_master WHERE name = 'visit'").Scan(&createSql); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(createSql, "CONSTRAINT `fk_visit_user_id_study_id` FOREIGN KEY") {
+		t.Errorf("Expected foreign key to be added but got %s", createSql)
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM visit").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 rows to be copied but got %d", count)
+	}
+	if err := db.QueryRow("SELECT sum(total) FROM visit").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("Expected generated column to be recomputed but got %d", count)
+	}
+	if err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE name = 'idx_visit_user_id'").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected index to be recreated")
+	}
+}
+
+func TestSqliteRebuildViews(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("u", func(t *Table) {
+			t.Primary("id")
+			t.String("name")
+		})
+		s.CreateView("v", "SELECT id, name FROM u")
+		s.CreateView("w", "SELECT name FROM v")
+	})
+	if _, err := db.Exec("CREATE TRIGGER v_insert INSTEAD OF INSERT ON v BEGIN INSERT INTO u (name) VALUES (new.name); END"); err != nil {
+		t.Fatal(err)
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Table("u", func(t *Table) {
+			t.Check("name <> ''").Name("chk_name")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO v (name) VALUES ('ada')"); err != nil {
+		t.Fatalf("Expected the view trigger to be recreated: %s", err)
+	}
+	var name string
+	if err := db.QueryRow("SELECT name FROM w").Scan(&name); err != nil {
+		t.Fatalf("Expected the views to be recreated: %s", err)
+	}
+	if name != "ada" {
+		t.Errorf("Expected ada but got %s", name)
+	}
+}
+
+func TestSqliteRebuildChecks(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("person", func(t *Table) {
+			t.Integer("age").NamedCheck("chk_age", "age >= 0")
+			t.String("status")
+			t.Check("status IN ('active', 'inactive')").Name("chk_status")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO person (age, status) VALUES (-1, 'active')"); err == nil {
+		t.Fatal("Expected column check to be enforced")
+	}
+	runSqlite(t, db, func(s *Schema) {
+		s.Table("person", func(t *Table) {
+			t.DropCheck("chk_age")
+			t.DropCheck("chk_status")
+			t.Check("status <> ''")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO person (age, status) VALUES (-1, 'unknown')"); err != nil {
+		t.Errorf("Expected checks to be dropped: %s", err)
+	}
+	if _, err := db.Exec("INSERT INTO person (age, status) VALUES (1, '')"); err == nil {
+		t.Error("Expected new check to be enforced")
+	}
+}
+
+func TestSqliteRebuildForeignKeysEnabled(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("org", func(t *Table) {
+			t.Primary("id")
+			t.Integer("parent_id").Null()
+		})
+		s.Create("member", func(t *Table) {
+			t.Integer("org_id").References("org", "id").OnDelete(CASCADE{})
+		})
+	})
+	if _, err := db.Exec("INSERT INTO org (id) VALUES (1); INSERT INTO member (org_id) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(driver.TypeSqlite3, "test")
+	s.Table("org", func(t *Table) {
+		t.Foreign("parent_id").References("org", "id")
+	})
+	err = s.Schema.Run(tx, nil)
+	tx.Rollback()
+	if !errors.Is(err, ErrForeignKeysEnabled) {
+		t.Errorf("Expected the rebuild to fail while foreign keys are enabled but got %v", err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM member").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected referencing rows to be kept but got %d", count)
+	}
+}
+
+func TestSqliteRebuildForeignKeyCheck(t *testing.T) {
+	db := openSqlite(t)
+	runSqlite(t, db, func(s *Schema) {
+		s.Create("org", func(t *Table) {
+			t.Primary("id")
+		})
+		s.Create("member", func(t *Table) {
+			t.Integer("org_id")
+		})
+	})
+	if _, err := db.Exec("INSERT INTO member (org_id) VALUES (2)"); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	s := New(driver.TypeSqlite3, "test")
+	s.Table("member", func(t *Table) {
+		t.Foreign("org_id").References("org", "id")
+	})
+	if err = s.Schema.Run(tx, nil); err == nil {
+		t.Error("Expected rows violating the new foreign key to fail the rebuild")
+	}
+}
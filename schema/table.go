@@ -3,6 +3,7 @@ package schema
 import (
 	"bytes"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"strings"
@@ -19,6 +20,40 @@ type TableDef struct {
 	IfNotExists  bool
 	Columns      []*columnDef
 	Indices      []*indexDef
+	ForeignKeys  []*foreignDef
+	Checks       []*checkDef
+	Searches     []*searchDef
+	Comment      string
+	// DroppingChecks are the names of check constraints to remove from an existing table
+	DroppingChecks   []string
+	DroppingColumns  []string
+	DroppingIndices  []string
+	DroppingForeigns []string
+	DroppingSearches []string
+	DroppingPolicies []string
+	// Partitioning and the Partitions created along with the table are used by MySQL and Postgres
+	Partitioning *partitioningDef
+	Partitions   []*PartitionDef
+	// MySQL options
+	Engine    string
+	Charset   string
+	Collation string
+	RowFormat string
+	// SQLite options
+	Strict       bool
+	WithoutRowid bool
+	// Postgres options
+	Unlogged   bool
+	Tablespace string
+	Params     []indexParam
+	// Postgres row level security
+	Policies           []*policyDef
+	EnableRowSecurity  bool
+	ForceRowSecurity   bool
+	NoForceRowSecurity bool
+	DisableRowSecurity bool
+	// deferredForeigns are added after the table is created because they are part of a reference cycle
+	deferredForeigns []string
 }
 
 type Table struct {
@@ -29,7 +64,7 @@ type Table struct {
 func (t *Table) Index(cols ...string) *indexBuilder {
 	i := &indexDef{
 		Table:   t.tableDef,
-		Columns: cols,
+		Columns: newIndexColumns(cols),
 	}
 	t.tableDef.Indices = append(t.tableDef.Indices, i)
 	return &indexBuilder{i}
@@ -40,12 +75,174 @@ func (t *Table) Unique(cols ...string) *indexBuilder {
 	i := &indexDef{
 		Table:   t.tableDef,
 		Unique:  true,
-		Columns: cols,
+		Columns: newIndexColumns(cols),
 	}
 	t.tableDef.Indices = append(t.tableDef.Indices, i)
 	return &indexBuilder{i}
 }
 
+// Create a foreign key on this table using the given columns. This is the table level equivalent of
+// columnBuilder.References and is needed for keys spanning multiple columns.
+func (t *Table) Foreign(cols ...string) *foreignBuilder {
+	f := &foreignDef{
+		Table:   t.tableDef,
+		Columns: cols,
+	}
+	t.tableDef.ForeignKeys = append(t.tableDef.ForeignKeys, f)
+	return &foreignBuilder{f}
+}
+
+// Create a check constraint on this table. The expression may reference any column of the table.
+func (t *Table) Check(expr string) *checkBuilder {
+	c := &checkDef{
+		Table: t.tableDef,
+		Expr:  expr,
+	}
+	t.tableDef.Checks = append(t.tableDef.Checks, c)
+	return &checkBuilder{c}
+}
+
+// Search creates a full-text search index named name over the given text columns. The name is used for the SQLite
+// FTS5 table, the MySQL FULLTEXT index and the Postgres tsvector column. SQLite FTS5 tables aren't dropped with their
+// table, so drop them with DropSearch first. github.com/mattn/go-sqlite3 only includes FTS5 when built with
+// -tags sqlite_fts5, otherwise running the schema fails with ErrNoFTS5.
+func (t *Table) Search(name string, cols ...string) *searchBuilder {
+	s := &searchDef{
+		Table:   t.tableDef,
+		Name:    name,
+		Columns: cols,
+	}
+	t.tableDef.Searches = append(t.tableDef.Searches, s)
+	return &searchBuilder{s}
+}
+
+// Drop a full-text search index of this table by name
+func (t *Table) DropSearch(name string) {
+	t.tableDef.DroppingSearches = append(t.tableDef.DroppingSearches, name)
+}
+
+// PartitionBy splits the rows of the table into partitions by the given columns. Partitions are declared with Partition
+// or Schema.CreatePartition. MySQL requires range and list partitioned tables to be created with their partitions.
+// SQLite doesn't support partitioning.
+func (t *Table) PartitionBy(method PartitionMethod, cols ...string) {
+	t.tableDef.Partitioning = &partitioningDef{Method: method, Columns: cols}
+}
+
+// Partition declares a partition created along with the table
+func (t *Table) Partition(name string, fn PartitionMutator) {
+	p := &PartitionDef{Schema: t.tableDef.Schema, Table: t.tableDef.Name, Name: name}
+	t.tableDef.Partitions = append(t.tableDef.Partitions, p)
+	if fn != nil {
+		fn(&Partition{p})
+	}
+}
+
+// EnableRowSecurity limits the rows of this Postgres table to the ones allowed by its policies. Tables without
+// policies deny access to every row. The owner of the table bypasses the policies unless ForceRowSecurity is used.
+func (t *Table) EnableRowSecurity() {
+	t.tableDef.EnableRowSecurity = true
+}
+
+// ForceRowSecurity applies the policies of this Postgres table to its owner too
+func (t *Table) ForceRowSecurity() {
+	t.tableDef.ForceRowSecurity = true
+}
+
+// NoForceRowSecurity lets the owner of this Postgres table bypass its policies again
+func (t *Table) NoForceRowSecurity() {
+	t.tableDef.NoForceRowSecurity = true
+}
+
+func (t *Table) DisableRowSecurity() {
+	t.tableDef.DisableRowSecurity = true
+}
+
+// Policy creates a Postgres row level security policy on this table
+func (t *Table) Policy(name string) *policyBuilder {
+	p := &policyDef{
+		Table: t.tableDef,
+		Name:  name,
+	}
+	t.tableDef.Policies = append(t.tableDef.Policies, p)
+	return &policyBuilder{p}
+}
+
+// Drop a row level security policy of this table by name
+func (t *Table) DropPolicy(name string) {
+	t.tableDef.DroppingPolicies = append(t.tableDef.DroppingPolicies, name)
+}
+
+// Drop a named check constraint from this table. Column level checks can be dropped by name as well.
+func (t *Table) DropCheck(name string) {
+	t.tableDef.DroppingChecks = append(t.tableDef.DroppingChecks, name)
+}
+
+// Drop a column from this table. SQLite 3.35 or newer is required and the column can't be indexed or part of a
+// constraint.
+func (t *Table) DropColumn(name string) {
+	t.tableDef.DroppingColumns = append(t.tableDef.DroppingColumns, name)
+}
+
+// Drop an index of this table by name
+func (t *Table) DropIndex(name string) {
+	t.tableDef.DroppingIndices = append(t.tableDef.DroppingIndices, name)
+}
+
+// Drop a foreign key of this table by its constraint name, like fk_<table>_<column>
+func (t *Table) DropForeign(name string) {
+	t.tableDef.DroppingForeigns = append(t.tableDef.DroppingForeigns, name)
+}
+
+// Comment describes this table in the database catalog
+func (t *Table) Comment(comment string) {
+	t.tableDef.Comment = comment
+}
+
+// Engine sets the MySQL storage engine. Options for other drivers are ignored when rendering the table.
+func (t *Table) Engine(engine string) {
+	t.tableDef.Engine = engine
+}
+
+// Charset sets the default MySQL character set of the table
+func (t *Table) Charset(charset string) {
+	t.tableDef.Charset = charset
+}
+
+// Collate sets the default MySQL collation of the table
+func (t *Table) Collate(collation string) {
+	t.tableDef.Collation = collation
+}
+
+// RowFormat sets the MySQL row format, like DYNAMIC or COMPRESSED
+func (t *Table) RowFormat(format string) {
+	t.tableDef.RowFormat = format
+}
+
+// Strict enforces column types on SQLite 3.37 or newer. Fixed point columns are stored as ANY in strict tables.
+func (t *Table) Strict() {
+	t.tableDef.Strict = true
+}
+
+// WithoutRowid creates a SQLite table without the implicit rowid column. These tables require a primary key.
+func (t *Table) WithoutRowid() {
+	t.tableDef.WithoutRowid = true
+}
+
+// Unlogged creates a Postgres table that skips the write-ahead log. Unlogged tables are truncated after a crash.
+func (t *Table) Unlogged() {
+	t.tableDef.Unlogged = true
+}
+
+// Tablespace sets the Postgres tablespace of the table
+func (t *Table) Tablespace(name string) {
+	t.tableDef.Tablespace = name
+}
+
+// With sets a Postgres storage parameter, like fillfactor
+func (t *Table) With(param string, value interface{}) {
+	t.tableDef.Params = append(t.tableDef.Params, indexParam{Name: param, Value: value})
+}
+
 // Create a (or modify) column on this table
 func (t *Table) Column(name string, mods ...ColumnMod) *columnBuilder {
 	c := &columnDef{
@@ -91,6 +288,34 @@ func (t *Table) Integer(name string, mods ...ColumnMod) *columnBuilder {
 	return t.Column(name, mods...)
 }
 
+// Decimal creates a fixed point column with the given number of digits, scale of which are after the decimal point
+func (t *Table) Decimal(name string, precision, scale int, mods ...ColumnMod) *columnBuilder {
+	mods = append([]ColumnMod{Decimal(), Precision(precision), Scale(scale)}, mods...)
+	return t.Column(name, mods...)
+}
+
+// Numeric creates a fixed point column with the given number of digits, scale of which are after the decimal point
+func (t *Table) Numeric(name string, precision, scale int, mods ...ColumnMod) *columnBuilder {
+	mods = append([]ColumnMod{Numeric(), Precision(precision), Scale(scale)}, mods...)
+	return t.Column(name, mods...)
+}
+
+// Float creates a floating point column. Use the Precision modifier to set the minimum precision in bits.
+func (t *Table) Float(name string, mods ...ColumnMod) *columnBuilder {
+	mods = append([]ColumnMod{Float()}, mods...)
+	return t.Column(name, mods...)
+}
+
+func (t *Table) Double(name string, mods ...ColumnMod) *columnBuilder {
+	mods = append([]ColumnMod{Double()}, mods...)
+	return t.Column(name, mods...)
+}
+
+func (t *Table) Bit(name string, n int, mods ...ColumnMod) *columnBuilder {
+	mods = append([]ColumnMod{Bit(), Length(n)}, mods...)
+	return t.Column(name, mods...)
+}
+
 func (t *Table) VarChar(name string, n int, mods ...ColumnMod) *columnBuilder {
 	mods = append([]ColumnMod{VarChar(n)}, mods...)
 	return t.Column(name, mods...)
@@ -137,113 +362,518 @@ func (t *Table) VarBinary(name string, mods ...ColumnMod) *columnBuilder {
 }
 
 func (t *TableDef) NumPrimary() int {
-	res := 0
+	return len(t.PrimaryColumns())
+}
+
+// PrimaryColumns returns the columns in the primary key in the order they were added
+func (t *TableDef) PrimaryColumns() (columns []*columnDef) {
 	for _, c := range t.Columns {
 		if c.IsPrimary {
-			res++
+			columns = append(columns, c)
+		}
+	}
+	return
+}
+
+// Foreigns returns every foreign key on this table. Column references come first, followed by table level keys.
+func (t *TableDef) Foreigns() (foreigns []*foreignDef) {
+	for _, c := range t.Columns {
+		if f := c.Foreign(); f != nil {
+			foreigns = append(foreigns, f)
+		}
+	}
+	return append(foreigns, t.ForeignKeys...)
+}
+
+// enums returns the Postgres types created for the enum columns added to this table. Types shared with other columns
+// or created earlier in the schema are only created once.
+func (t *TableDef) enums() (enums []*EnumDef) {
+	created := t.Schema.enumsBefore(t)
+	for _, e := range t.columnEnums() {
+		if !created[e.Name] {
+			created[e.Name] = true
+			enums = append(enums, e)
 		}
 	}
-	return res
+	return
 }
 
-func (t *TableDef) Statements() (statements []string) {
+// columnEnums returns the types of the enum columns added to this table. Modified columns already exist so they don't
+// create their types.
+func (t *TableDef) columnEnums() (enums []*EnumDef) {
+	if t.Schema.Driver != driver.TypePostgres {
+		return
+	}
+	for _, c := range t.Columns {
+		if e := c.Enum(); e != nil && (t.WillCreate || (c.OriginalName == c.Name && !c.IsModified)) {
+			enums = append(enums, e)
+		}
+	}
+	return
+}
+
+// dropSearches drops the full-text search indices created by this table
+func (t *TableDef) dropSearches() (statements []Statement, err error) {
+	tmp, err := t.Schema.loadTemplates()
+	if err != nil {
+		return
+	}
+	for _, search := range t.Searches {
+		var steps []Statement
+		if steps, err = search.dropStatements(tmp); err != nil {
+			return
+		}
+		statements = append(statements, steps...)
+	}
+	return
+}
+
+// InlineForeigns returns the foreign keys created along with the table
+func (t *TableDef) InlineForeigns() (foreigns []*foreignDef) {
+	for _, f := range t.Foreigns() {
+		deferred := false
+		for _, name := range t.deferredForeigns {
+			deferred = deferred || name == f.GetName()
+		}
+		if !deferred {
+			foreigns = append(foreigns, f)
+		}
+	}
+	return
+}
+
+func (t *TableDef) Statements() (statements []string, err error) {
+	steps, err := t.steps()
+	for _, statement := range steps {
+		statements = append(statements, statement.Sql)
+	}
+	return
+}
+
+func (t *TableDef) steps() (statements []Statement, err error) {
+	if err = t.validate(); err != nil {
+		return
+	}
+	tmp, err := t.Schema.loadTemplates()
+	if err != nil {
+		return
+	}
+	// Postgres enum columns need their type to exist first
+	for _, e := range t.enums() {
+		var sql string
+		if sql, err = e.createStatement(); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
+	}
+	var steps []Statement
 	if t.WillCreate {
-		statements = append(statements, t.createStatement())
+		steps, err = t.createStatements(tmp)
 	} else {
-		statements = append(statements, t.alterStatement())
+		steps, err = t.alterStatements(tmp)
+	}
+	if err != nil {
+		return
 	}
-	statements = append(statements, t.indexStatements()...)
+	statements = append(statements, steps...)
+	for _, idx := range t.Indices {
+		var sql string
+		if sql, err = execTemplate(tmp, "create_index", idx); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
+	}
+	for _, search := range t.Searches {
+		if steps, err = search.statements(tmp); err != nil {
+			return
+		}
+		statements = append(statements, steps...)
+	}
+	if steps, err = t.securityStatements(tmp); err != nil {
+		return
+	}
+	statements = append(statements, steps...)
 	return
 }
 
+// validate checks that the table only uses columns it defines and only references columns of tables created by the
+// same schema. Columns of existing tables aren't known, so they aren't checked.
+func (t *TableDef) validate() error {
+	errs := []error{}
+	types, err := t.Schema.types()
+	if err != nil {
+		return err
+	}
+	if t.WillCreate && len(t.Columns) == 0 {
+		errs = append(errs, fmt.Errorf("table %s has no columns", t.Name))
+	}
+	for _, c := range t.Columns {
+		if c.Kind == TypeCustom && c.CustomType == "" {
+			errs = append(errs, fmt.Errorf("column %s.%s has no custom type", t.Name, c.Name))
+		}
+		if c.Kind == TypeCustom && t.Schema.Driver != driver.TypePostgres {
+			errs = append(errs, fmt.Errorf("column %s.%s: %w", t.Name, c.Name, unsupported("custom types", t.Schema.Driver)))
+		}
+		if _, ok := types[c.Kind]; !ok && c.Kind != TypeEnum && c.Kind != TypeCustom {
+			errs = append(errs, fmt.Errorf("column %s.%s: type %d is %w by %s", t.Name, c.Name, c.Kind, ErrUnsupported, t.Schema.Driver))
+		}
+		if c.IsModified && (t.WillCreate || c.OriginalName != c.Name || c.ReferenceTo != nil) {
+			errs = append(errs, fmt.Errorf("column %s.%s can only be modified in an existing table without renaming it or adding a reference", t.Name, c.Name))
+		}
+		if c.GeneratedAs != "" && c.DefaultVal != nil {
+			errs = append(errs, fmt.Errorf("generated column %s.%s can't have a default", t.Name, c.Name))
+		}
+		if c.GeneratedAs != "" && c.IsStored && !t.WillCreate && c.OriginalName == c.Name && t.Schema.Driver == driver.TypeSqlite3 {
+			errs = append(errs, fmt.Errorf("column %s.%s: adding stored generated columns to a table is %w by %s", t.Name, c.Name, ErrUnsupported, t.Schema.Driver))
+		}
+		if c.Kind == TypeEnum && len(c.EnumValues) == 0 && (c.EnumName == "" || t.Schema.Driver != driver.TypePostgres) {
+			errs = append(errs, fmt.Errorf("enum column %s.%s has no values", t.Name, c.Name))
+		}
+	}
+	for _, search := range t.Searches {
+		errs = append(errs, search.validate()...)
+	}
+	if t.Partitioning != nil && t.WillCreate {
+		for _, col := range t.Partitioning.Columns {
+			if t.column(col) == nil {
+				errs = append(errs, fmt.Errorf("table %s is partitioned by unknown column %s", t.Name, col))
+			}
+		}
+	}
+	if t.Partitioning == nil && len(t.Partitions) > 0 {
+		errs = append(errs, fmt.Errorf("table %s has partitions but isn't partitioned", t.Name))
+	}
+	if t.Partitioning != nil {
+		for _, p := range t.Partitions {
+			errs = append(errs, p.validate(t.Partitioning.Method)...)
+		}
+	}
+	if t.WillCreate {
+		for _, idx := range t.Indices {
+			for _, col := range idx.Columns {
+				if col.Expr == "" && t.column(col.Name) == nil {
+					errs = append(errs, fmt.Errorf("index %s uses unknown column %s", idx.GetName(), col.Name))
+				}
+			}
+			for _, col := range idx.Include {
+				if t.column(col) == nil {
+					errs = append(errs, fmt.Errorf("index %s includes unknown column %s", idx.GetName(), col))
+				}
+			}
+		}
+		for _, f := range t.ForeignKeys {
+			for _, col := range f.Columns {
+				if t.column(col) == nil {
+					errs = append(errs, fmt.Errorf("foreign key %s uses unknown column %s", f.GetName(), col))
+				}
+			}
+		}
+	}
+	for _, f := range t.Foreigns() {
+		if f.RefTable == "" {
+			errs = append(errs, fmt.Errorf("foreign key %s doesn't reference a table", f.GetName()))
+			continue
+		}
+		if len(f.Columns) != len(f.RefColumns) {
+			errs = append(errs, fmt.Errorf("foreign key %s has %d columns but references %d", f.GetName(), len(f.Columns), len(f.RefColumns)))
+		}
+		if ref := t.Schema.createdTable(f.RefTable); ref != nil {
+			for _, col := range f.RefColumns {
+				if ref.column(col) == nil {
+					errs = append(errs, fmt.Errorf("foreign key %s references unknown column %s.%s", f.GetName(), f.RefTable, col))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t *TableDef) column(name string) *columnDef {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
 //go:embed templates/*
 var templates embed.FS
 
-func sqliteFuncMap() template.FuncMap {
+func funcMap(driverType driver.Type, types typeMap) template.FuncMap {
 	return template.FuncMap{
-		"GetType": func(kind ColumnType, num int) string {
-			res := sqliteTypeMap[kind]
-			if res == "VARCHAR" || res == "NVARCHAR" {
-				res += fmt.Sprintf("(%d)", num)
+		"GetType": func(c *columnDef) string {
+			if c.Kind == TypeCustom {
+				if nonIdentifierChars.MatchString(c.CustomType) {
+					return c.CustomType
+				}
+				return fmt.Sprintf(`"%s"`, c.CustomType)
 			}
-			return res
-		},
-		"GetDefault": func(kind ColumnType, val interface{}) string {
-			if val == nil {
-				return ""
-			}
-			switch kind {
-			case TypeVarChar, TypeNVarChar, TypeText, TypeJson, TypeEnum:
-				return fmt.Sprintf(" DEFAULT '%s'", val)
-			case TypeInteger, TypeBigInt, TypeDecimal, TypeTinyInt, TypeFloat:
-				return fmt.Sprintf(" DEFAULT %v", val)
-			case TypeBoolean:
-				if val.(bool) {
-					return " DEFAULT TRUE"
-				} else {
-					return " DEFAULT FALSE"
+			if c.Kind == TypeEnum {
+				switch driverType {
+				case driver.TypeMysql:
+					return fmt.Sprintf("ENUM(%s)", quoteStrings(driverType, c.EnumValues))
+				case driver.TypePostgres:
+					return fmt.Sprintf(`"%s"`, c.EnumTypeName())
 				}
-			case TypeDateTime, TypeDate, TypeTime, TypeTimestamp:
-				c, ok := val.(Constant)
-				if !ok {
-					return fmt.Sprintf(" DEFAULT '%s'", val)
+			}
+			res := types[c.Kind]
+			if driverType == driver.TypeSqlite3 && c.Table().Strict {
+				res = sqliteStrictTypeMap[c.Kind]
+			}
+			// Postgres REAL has a fixed precision
+			if c.Kind == TypeFloat && c.Precision > 0 && driverType == driver.TypePostgres {
+				res = "FLOAT"
+			}
+			switch res {
+			case "VARCHAR", "NVARCHAR":
+				res += fmt.Sprintf("(%d)", c.KindLen)
+			case "BINARY", "VARBINARY", "BIT":
+				if c.KindLen > 0 {
+					res += fmt.Sprintf("(%d)", c.KindLen)
+				}
+			case "DECIMAL", "NUMERIC":
+				if c.Precision > 0 && c.Scale > 0 {
+					res += fmt.Sprintf("(%d,%d)", c.Precision, c.Scale)
+				} else if c.Precision > 0 {
+					res += fmt.Sprintf("(%d)", c.Precision)
+				}
+			case "FLOAT":
+				if c.Precision > 0 {
+					res += fmt.Sprintf("(%d)", c.Precision)
 				}
-				return fmt.Sprintf(" DEFAULT %s", c.Constant(driver.TypeSqlite3))
-			default:
-				return ""
 			}
+			return res
+		},
+		"GetDefault": func(kind ColumnType, val interface{}) (string, error) {
+			res, err := defaultValue(driverType, kind, val)
+			if res == "" || err != nil {
+				return "", err
+			}
+			return " DEFAULT " + res, nil
+		},
+		"Action": func(action FkAction) string {
+			return action.Action(driverType)
+		},
+		"unsupported": func(feature string) (string, error) {
+			return "", unsupported(feature, driverType)
 		},
 		"join": strings.Join,
+		"Quote": func(s string) string {
+			return quoteString(driverType, s)
+		},
+		"BlockComment": func(s string) string {
+			return "/* " + strings.ReplaceAll(s, "*/", "* /") + " */"
+		},
+		"QuoteAll": func(values []interface{}) string {
+			return quoteStrings(driverType, values)
+		},
+		"QuoteRole": func(role string) string {
+			return quoteRole(driverType, role)
+		},
 	}
 }
 
-func (t *TableDef) loadTemplates() (tmp *template.Template) {
-	dirFs, err := fs.Sub(templates, "templates")
-	if err != nil {
-		panic(err)
-	}
-	var funcMap template.FuncMap
-	switch t.Schema.Driver {
+func (s *SchemaDef) types() (types typeMap, err error) {
+	switch s.Driver {
 	case driver.TypeMysql:
-		// TODO
+		types = mysqlTypeMap
 	case driver.TypePostgres:
-		// TODO
+		types = postgresTypeMap
 	case driver.TypeSqlite3:
-		funcMap = sqliteFuncMap()
+		types = sqliteTypeMap
 	default:
-		panic("unknown driver type")
+		err = fmt.Errorf("unknown driver type %s", s.Driver)
+	}
+	return
+}
+
+func (s *SchemaDef) loadTemplates() (tmp *template.Template, err error) {
+	types, err := s.types()
+	if err != nil {
+		return
 	}
-	tmp = template.New("table").Funcs(funcMap)
-	tmpName := fmt.Sprintf("%s.tpl", t.Schema.Driver)
-	tmp, err = tmp.ParseFS(dirFs, tmpName)
+	dirFs, err := fs.Sub(templates, "templates")
 	if err != nil {
-		panic(err)
+		return
 	}
-	return
+	tmp = template.New("table").Funcs(funcMap(s.Driver, types))
+	tmpName := fmt.Sprintf("%s.tpl", s.Driver)
+	return tmp.ParseFS(dirFs, tmpName)
 }
 
-func (t *TableDef) createStatement() (s string) {
-	tmp := t.loadTemplates()
+func execTemplate(tmp *template.Template, name string, data interface{}) (string, error) {
 	res := bytes.Buffer{}
-	if err := tmp.ExecuteTemplate(&res, "create_table", t); err != nil {
-		panic(err)
+	if err := tmp.ExecuteTemplate(&res, name, data); err != nil {
+		return "", err
 	}
-	s = res.String()
-	return
+	return res.String(), nil
 }
 
-func (t *TableDef) alterStatement() string {
-	return ""
+// appendStatement renders the named template with data and appends it to statements. Drivers leave out the templates
+// for statements they don't need, so a missing template adds nothing.
+func (s *SchemaDef) appendStatement(statements []Statement, tmp *template.Template, name string, data interface{}) ([]Statement, error) {
+	if tmp.Lookup(name) == nil {
+		return statements, nil
+	}
+	sql, err := execTemplate(tmp, name, data)
+	if err != nil {
+		return statements, err
+	}
+	return append(statements, Statement{Sql: sql}), nil
 }
 
-func (t *TableDef) indexStatements() (statements []string) {
-	tmp := t.loadTemplates()
-	for _, idx := range t.Indices {
-		res := bytes.Buffer{}
-		if err := tmp.ExecuteTemplate(&res, "create_index", idx); err != nil {
-			panic(err)
+// createStatements creates the table. Postgres comments are separate statements while other drivers comment inline.
+func (t *TableDef) createStatements(tmp *template.Template) (statements []Statement, err error) {
+	sql, err := execTemplate(tmp, "create_table", t)
+	if err != nil {
+		return
+	}
+	statements = append(statements, Statement{Sql: sql})
+	// MySQL declares partitions in CREATE TABLE while Postgres creates each partition as a table
+	if t.Schema.Driver == driver.TypePostgres {
+		for _, p := range t.Partitions {
+			if sql, err = execTemplate(tmp, "create_partition", p); err != nil {
+				return
+			}
+			statements = append(statements, Statement{Sql: sql})
+		}
+	}
+	if tmp.Lookup("comment_column") == nil {
+		return
+	}
+	if t.Comment != "" {
+		if sql, err = execTemplate(tmp, "comment_table", t); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
+	}
+	for _, col := range t.Columns {
+		if col.Comment != "" {
+			if sql, err = execTemplate(tmp, "comment_column", col); err != nil {
+				return
+			}
+			statements = append(statements, Statement{Sql: sql})
+		}
+	}
+	return
+}
+
+// alterStatements adds, modifies and renames columns and adds or drops constraints. SQLite can't change the constraints
+// or columns of an existing table so it rebuilds the table instead.
+func (t *TableDef) alterStatements(tmp *template.Template) (statements []Statement, err error) {
+	isSqlite := t.Schema.Driver == driver.TypeSqlite3
+	add := func(name string, data interface{}) (err error) {
+		sql, err := execTemplate(tmp, name, data)
+		if err == nil {
+			statements = append(statements, Statement{Sql: sql})
+		}
+		return
+	}
+	var foreigns []*foreignDef
+	var modified []string
+	for _, name := range t.DroppingSearches {
+		search := &searchDef{Table: t, Name: name}
+		var steps []Statement
+		if steps, err = search.dropStatements(tmp); err != nil {
+			return
+		}
+		statements = append(statements, steps...)
+	}
+	for _, name := range t.DroppingIndices {
+		if err = add("drop_index", &indexDef{Table: t, Name: name}); err != nil {
+			return
+		}
+	}
+	if !isSqlite {
+		for _, name := range t.DroppingForeigns {
+			if err = add("drop_foreign", &foreignDef{Table: t, Name: name}); err != nil {
+				return
+			}
+		}
+	}
+	if t.Comment != "" {
+		if err = add("comment_table", t); err != nil {
+			return
+		}
+	}
+	for _, col := range t.Columns {
+		if col.IsModified && isSqlite {
+			var sql string
+			if sql, err = execTemplate(tmp, "column", col); err != nil {
+				return
+			}
+			modified = append(modified, strings.TrimSpace(sql))
+			continue
+		}
+		if col.IsModified {
+			if err = add("modify_column", col); err != nil {
+				return
+			}
+			continue
+		}
+		if col.OriginalName != col.Name {
+			if err = add("rename_column", col); err != nil {
+				return
+			}
+			continue
+		}
+		if err = add("add_column", col); err != nil {
+			return
+		}
+		if col.Comment != "" && tmp.Lookup("comment_column") != nil {
+			if err = add("comment_column", col); err != nil {
+				return
+			}
+		}
+		// SQLite adds the reference as part of the column definition instead
+		if f := col.Foreign(); f != nil && !isSqlite {
+			foreigns = append(foreigns, f)
+		}
+	}
+	foreigns = append(foreigns, t.ForeignKeys...)
+	if isSqlite {
+		drops := append(append([]string{}, t.DroppingForeigns...), t.DroppingChecks...)
+		if len(foreigns) > 0 || len(t.Checks) > 0 || len(drops) > 0 || len(modified) > 0 {
+			rebuild := &sqliteRebuild{Table: t.Name, DropConstraints: drops, ModifyColumns: modified}
+			for _, f := range foreigns {
+				var sql string
+				if sql, err = execTemplate(tmp, "foreign", f); err != nil {
+					return
+				}
+				rebuild.AddConstraints = append(rebuild.AddConstraints, sql)
+			}
+			for _, c := range t.Checks {
+				var sql string
+				if sql, err = execTemplate(tmp, "check", c); err != nil {
+					return
+				}
+				rebuild.AddConstraints = append(rebuild.AddConstraints, sql)
+			}
+			statements = append(statements, rebuild.statement())
+		}
+	} else {
+		for _, name := range t.DroppingChecks {
+			if err = add("drop_check", &checkDef{Table: t, Name: name}); err != nil {
+				return
+			}
+		}
+		for _, f := range foreigns {
+			if err = add("add_foreign", f); err != nil {
+				return
+			}
+		}
+		for _, c := range t.Checks {
+			if err = add("add_check", c); err != nil {
+				return
+			}
+		}
+	}
+	// columns are dropped once the constraints using them are gone
+	for _, name := range t.DroppingColumns {
+		if err = add("drop_column", &columnDef{table: &Table{t}, OriginalName: name, Name: name}); err != nil {
+			return
 		}
-		statements = append(statements, res.String())
 	}
 	return
 }
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -9,10 +10,12 @@ import (
 )
 
 type testStatement struct {
-	Table        string
-	Create       TableMutator
-	Alter        TableMutator
-	SqliteResult string
+	Table          string
+	Create         TableMutator
+	Alter          TableMutator
+	SqliteResult   string
+	MysqlResult    string
+	PostgresResult string
 }
 
 var createStatements = []testStatement{
@@ -46,7 +49,9 @@ var createStatements = []testStatement{
 			t.String("id").Primary()
 			t.String("user_id").Primary()
 		},
-		SqliteResult: "CREATE TABLE `multiple_primary` (\n'id' TEXT NOT NULL,\n'user_id' TEXT NOT NULL,\nPRIMARY KEY ('id', 'user_id')\n);",
+		SqliteResult:   "CREATE TABLE `multiple_primary` (\n'id' TEXT NOT NULL,\n'user_id' TEXT NOT NULL,\nPRIMARY KEY ('id', 'user_id')\n);",
+		MysqlResult:    "CREATE TABLE `multiple_primary` (\n`id` VARCHAR(255) NOT NULL,\n`user_id` VARCHAR(255) NOT NULL,\nPRIMARY KEY (`id`, `user_id`)\n);",
+		PostgresResult: "CREATE TABLE \"multiple_primary\" (\n\"id\" VARCHAR(255) NOT NULL,\n\"user_id\" VARCHAR(255) NOT NULL,\nPRIMARY KEY (\"id\", \"user_id\")\n);",
 	},
 	{
 		Table: "single_unique",
@@ -72,7 +77,9 @@ var createStatements = []testStatement{
 			t.String("email")
 			t.Unique("username", "email")
 		},
-		SqliteResult: "CREATE TABLE `compound_unique` (\n'username' TEXT NOT NULL,\n'email' TEXT NOT NULL);CREATE UNIQUE INDEX 'unq_compound_unique_username_email' ON `compound_unique`('username', 'email');",
+		SqliteResult:   "CREATE TABLE `compound_unique` (\n'username' TEXT NOT NULL,\n'email' TEXT NOT NULL);CREATE UNIQUE INDEX 'unq_compound_unique_username_email' ON `compound_unique`('username', 'email');",
+		MysqlResult:    "CREATE TABLE `compound_unique` (\n`username` VARCHAR(255) NOT NULL,\n`email` VARCHAR(255) NOT NULL);CREATE UNIQUE INDEX `unq_compound_unique_username_email` ON `compound_unique`(`username`, `email`);",
+		PostgresResult: "CREATE TABLE \"compound_unique\" (\n\"username\" VARCHAR(255) NOT NULL,\n\"email\" VARCHAR(255) NOT NULL);CREATE UNIQUE INDEX \"unq_compound_unique_username_email\" ON \"compound_unique\"(\"username\", \"email\");",
 	},
 	{
 		Table: "single_index",
@@ -89,7 +96,7 @@ var createStatements = []testStatement{
 			t.Integer("user_id").References("users", "id")
 			t.String("username")
 		},
-		SqliteResult: "CREATE TABLE `single_foreign_key` (\n'id' INTEGER PRIMARY KEY,\n'user_id' INTEGER NOT NULL,\n'username' TEXT NOT NULL,\nFOREIGN KEY ('user_id') REFERENCES `users`('id'));\n",
+		SqliteResult: "CREATE TABLE `single_foreign_key` (\n'id' INTEGER PRIMARY KEY,\n'user_id' INTEGER NOT NULL,\n'username' TEXT NOT NULL,\nCONSTRAINT `fk_single_foreign_key_user_id` FOREIGN KEY ('user_id') REFERENCES `users`('id'));\n",
 	},
 	{
 		Table: "multiple_foreign_keys",
@@ -98,52 +105,458 @@ var createStatements = []testStatement{
 			t.Integer("study_id").References("study", "id")
 			t.Unique("user_id", "study_id")
 		},
-		SqliteResult: "CREATE TABLE `multiple_foreign_keys` (\n'user_id' INTEGER NOT NULL,\n'study_id' INTEGER NOT NULL,\nFOREIGN KEY ('user_id') REFERENCES `user`('id'),\nFOREIGN KEY ('study_id') REFERENCES `study`('id'));CREATE UNIQUE INDEX 'unq_multiple_foreign_keys_user_id_study_id' ON `multiple_foreign_keys`('user_id', 'study_id');\n",
+		SqliteResult: "CREATE TABLE `multiple_foreign_keys` (\n'user_id' INTEGER NOT NULL,\n'study_id' INTEGER NOT NULL,\nCONSTRAINT `fk_multiple_foreign_keys_user_id` FOREIGN KEY ('user_id') REFERENCES `user`('id'),\nCONSTRAINT `fk_multiple_foreign_keys_study_id` FOREIGN KEY ('study_id') REFERENCES `study`('id'));CREATE UNIQUE INDEX 'unq_multiple_foreign_keys_user_id_study_id' ON `multiple_foreign_keys`('user_id', 'study_id');\n",
+	},
+	{
+		Table: "composite_primary_key",
+		Create: func(t *Table) {
+			t.Integer("x")
+			t.Primary("a")
+			t.Primary("b")
+		},
+		SqliteResult:   "CREATE TABLE `composite_primary_key` (\n'x' INTEGER NOT NULL,\n'a' INTEGER NOT NULL,\n'b' INTEGER NOT NULL,\nPRIMARY KEY ('a', 'b'));",
+		MysqlResult:    "CREATE TABLE `composite_primary_key` (\n`x` INTEGER NOT NULL,\n`a` INTEGER NOT NULL,\n`b` INTEGER NOT NULL,\nPRIMARY KEY (`a`, `b`));",
+		PostgresResult: "CREATE TABLE \"composite_primary_key\" (\n\"x\" INTEGER NOT NULL,\n\"a\" INTEGER NOT NULL,\n\"b\" INTEGER NOT NULL,\nPRIMARY KEY (\"a\", \"b\"));",
+	},
+	{
+		Table: "foreign_key_actions",
+		Create: func(t *Table) {
+			t.Primary("id")
+			t.Integer("user_id").References("user", "id").OnDelete(CASCADE{}).OnUpdate(RESTRICT{})
+			t.Integer("study_id").Null().References("study", "id").OnDelete(SET_NULL{})
+		},
+		SqliteResult:   "CREATE TABLE `foreign_key_actions` (\n'id' INTEGER PRIMARY KEY,\n'user_id' INTEGER NOT NULL,\n'study_id' INTEGER NULL,\nCONSTRAINT `fk_foreign_key_actions_user_id` FOREIGN KEY ('user_id') REFERENCES `user`('id') ON DELETE CASCADE ON UPDATE RESTRICT,\nCONSTRAINT `fk_foreign_key_actions_study_id` FOREIGN KEY ('study_id') REFERENCES `study`('id') ON DELETE SET NULL);",
+		MysqlResult:    "CREATE TABLE `foreign_key_actions` (\n`id` INTEGER PRIMARY KEY,\n`user_id` INTEGER NOT NULL,\n`study_id` INTEGER NULL,\nCONSTRAINT `fk_foreign_key_actions_user_id` FOREIGN KEY (`user_id`) REFERENCES `user`(`id`) ON DELETE CASCADE ON UPDATE RESTRICT,\nCONSTRAINT `fk_foreign_key_actions_study_id` FOREIGN KEY (`study_id`) REFERENCES `study`(`id`) ON DELETE SET NULL);",
+		PostgresResult: "CREATE TABLE \"foreign_key_actions\" (\n\"id\" INTEGER PRIMARY KEY,\n\"user_id\" INTEGER NOT NULL,\n\"study_id\" INTEGER NULL,\nCONSTRAINT \"fk_foreign_key_actions_user_id\" FOREIGN KEY (\"user_id\") REFERENCES \"user\"(\"id\") ON DELETE CASCADE ON UPDATE RESTRICT,\nCONSTRAINT \"fk_foreign_key_actions_study_id\" FOREIGN KEY (\"study_id\") REFERENCES \"study\"(\"id\") ON DELETE SET NULL);",
+	},
+	{
+		Table: "composite_foreign_key",
+		Create: func(t *Table) {
+			t.Integer("user_id")
+			t.Integer("study_id")
+			t.Foreign("user_id", "study_id").References("membership", "user_id", "study_id").OnDelete(CASCADE{})
+		},
+		SqliteResult:   "CREATE TABLE `composite_foreign_key` (\n'user_id' INTEGER NOT NULL,\n'study_id' INTEGER NOT NULL,\nCONSTRAINT `fk_composite_foreign_key_user_id_study_id` FOREIGN KEY ('user_id', 'study_id') REFERENCES `membership`('user_id', 'study_id') ON DELETE CASCADE);",
+		MysqlResult:    "CREATE TABLE `composite_foreign_key` (\n`user_id` INTEGER NOT NULL,\n`study_id` INTEGER NOT NULL,\nCONSTRAINT `fk_composite_foreign_key_user_id_study_id` FOREIGN KEY (`user_id`, `study_id`) REFERENCES `membership`(`user_id`, `study_id`) ON DELETE CASCADE);",
+		PostgresResult: "CREATE TABLE \"composite_foreign_key\" (\n\"user_id\" INTEGER NOT NULL,\n\"study_id\" INTEGER NOT NULL,\nCONSTRAINT \"fk_composite_foreign_key_user_id_study_id\" FOREIGN KEY (\"user_id\", \"study_id\") REFERENCES \"membership\"(\"user_id\", \"study_id\") ON DELETE CASCADE);",
+	},
+	{
+		Table: "checks",
+		Create: func(t *Table) {
+			t.Integer("age").Check("age >= 0")
+			t.String("status").NamedCheck("chk_status", "status <> ''")
+			t.Check("age < 200 OR status = 'immortal'").Name("chk_age_status")
+		},
+		SqliteResult:   "CREATE TABLE `checks` (\n'age' INTEGER NOT NULL CHECK (age >= 0),\n'status' TEXT NOT NULL CONSTRAINT `chk_status` CHECK (status <> ''),\nCONSTRAINT `chk_age_status` CHECK (age < 200 OR status = 'immortal'));",
+		MysqlResult:    "CREATE TABLE `checks` (\n`age` INTEGER NOT NULL CHECK (age >= 0),\n`status` VARCHAR(255) NOT NULL CONSTRAINT `chk_status` CHECK (status <> ''),\nCONSTRAINT `chk_age_status` CHECK (age < 200 OR status = 'immortal'));",
+		PostgresResult: "CREATE TABLE \"checks\" (\n\"age\" INTEGER NOT NULL CHECK (age >= 0),\n\"status\" VARCHAR(255) NOT NULL CONSTRAINT \"chk_status\" CHECK (status <> ''),\nCONSTRAINT \"chk_age_status\" CHECK (age < 200 OR status = 'immortal'));",
+	},
+	{
+		Table: "generated_columns",
+		Create: func(t *Table) {
+			t.Json("data")
+			t.String("email").Null().Generated("data->>'email'")
+			t.Integer("age").Null().Generated("data->>'age'").Stored()
+		},
+		SqliteResult:   "CREATE TABLE `generated_columns` (\n'data' TEXT NOT NULL,\n'email' TEXT GENERATED ALWAYS AS (data->>'email') VIRTUAL NULL,\n'age' INTEGER GENERATED ALWAYS AS (data->>'age') STORED NULL);",
+		MysqlResult:    "CREATE TABLE `generated_columns` (\n`data` JSON NOT NULL,\n`email` VARCHAR(255) GENERATED ALWAYS AS (data->>'email') VIRTUAL NULL,\n`age` INTEGER GENERATED ALWAYS AS (data->>'age') STORED NULL);",
+		PostgresResult: "CREATE TABLE \"generated_columns\" (\n\"data\" JSONB NOT NULL,\n\"email\" VARCHAR(255) GENERATED ALWAYS AS (data->>'email') STORED NULL,\n\"age\" INTEGER GENERATED ALWAYS AS (data->>'age') STORED NULL);",
+	},
+	{
+		Table: "partial_expression_index",
+		Create: func(t *Table) {
+			t.String("email")
+			t.Timestamp("deleted_at").Null()
+			t.Unique().Expr("lower(email)").Where("deleted_at IS NULL")
+		},
+		SqliteResult:   "CREATE TABLE `partial_expression_index` (\n'email' TEXT NOT NULL,\n'deleted_at' TEXT NULL);CREATE UNIQUE INDEX 'unq_partial_expression_index_lower_email' ON `partial_expression_index`(lower(email)) WHERE deleted_at IS NULL;",
+		PostgresResult: "CREATE TABLE \"partial_expression_index\" (\n\"email\" VARCHAR(255) NOT NULL,\n\"deleted_at\" TIMESTAMP NULL);CREATE UNIQUE INDEX \"unq_partial_expression_index_lower_email\" ON \"partial_expression_index\"((lower(email))) WHERE deleted_at IS NULL;",
+	},
+	{
+		Table: "ordered_index",
+		Create: func(t *Table) {
+			t.String("name")
+			t.Timestamp("created_at")
+			t.Index("name", "created_at").Expr("lower(name)").Desc("created_at", "lower(name)").Name("idx_ordered")
+		},
+		SqliteResult:   "CREATE TABLE `ordered_index` (\n'name' TEXT NOT NULL,\n'created_at' TEXT NOT NULL);CREATE INDEX `idx_ordered` ON `ordered_index`('name', 'created_at' DESC, lower(name) DESC);",
+		MysqlResult:    "CREATE TABLE `ordered_index` (\n`name` VARCHAR(255) NOT NULL,\n`created_at` TIMESTAMP NOT NULL);CREATE INDEX `idx_ordered` ON `ordered_index`(`name`, `created_at` DESC, (lower(name)) DESC);",
+		PostgresResult: "CREATE TABLE \"ordered_index\" (\n\"name\" VARCHAR(255) NOT NULL,\n\"created_at\" TIMESTAMP NOT NULL);CREATE INDEX \"idx_ordered\" ON \"ordered_index\"(\"name\", \"created_at\" DESC, (lower(name)) DESC);",
+	},
+	{
+		Table: "collated_index",
+		Create: func(t *Table) {
+			t.String("username")
+			t.Index("username").Collate("username", "NOCASE").Name("idx_username")
+		},
+		SqliteResult: "CREATE TABLE `collated_index` (\n'username' TEXT NOT NULL);CREATE INDEX `idx_username` ON `collated_index`('username' COLLATE NOCASE);",
+	},
+	{
+		Table: "index_methods",
+		Create: func(t *Table) {
+			t.Integer("id")
+			t.Json("data")
+			t.Index("data").Using(IndexGin).Name("idx_data")
+			t.Unique("id").Include("data").With("fillfactor", 70).Name("unq_id")
+		},
+		PostgresResult: "CREATE TABLE \"index_methods\" (\n\"id\" INTEGER NOT NULL,\n\"data\" JSONB NOT NULL);CREATE INDEX \"idx_data\" ON \"index_methods\" USING gin(\"data\");CREATE UNIQUE INDEX \"unq_id\" ON \"index_methods\"(\"id\") INCLUDE (\"data\") WITH (fillfactor = 70);",
+	},
+	{
+		Table: "mysql_index_options",
+		Create: func(t *Table) {
+			t.Text("body")
+			t.String("slug")
+			t.Index("body").Fulltext().Name("idx_body")
+			t.Index("slug", "body").Prefix("body", 16).Using(IndexHash).Name("idx_slug_body")
+		},
+		MysqlResult: "CREATE TABLE `mysql_index_options` (\n`body` TEXT NOT NULL,\n`slug` VARCHAR(255) NOT NULL);CREATE FULLTEXT INDEX `idx_body` ON `mysql_index_options`(`body`);CREATE INDEX `idx_slug_body` USING hash ON `mysql_index_options`(`slug`, `body`(16));",
+	},
+	{
+		Table: "numeric_types",
+		Create: func(t *Table) {
+			t.Decimal("price", 10, 2)
+			t.Numeric("ratio", 5, 0)
+			t.Float("score", Precision(53))
+			t.Binary("uuid").Length(16)
+			t.VarBinary("token", Length(64))
+			t.Bit("flags", 8)
+		},
+		SqliteResult:   "CREATE TABLE `numeric_types` (\n'price' DECIMAL(10,2) NOT NULL,\n'ratio' NUMERIC(5) NOT NULL,\n'score' REAL NOT NULL,\n'uuid' BLOB NOT NULL,\n'token' BLOB NOT NULL,\n'flags' INTEGER NOT NULL);",
+		MysqlResult:    "CREATE TABLE `numeric_types` (\n`price` DECIMAL(10,2) NOT NULL,\n`ratio` NUMERIC(5) NOT NULL,\n`score` FLOAT(53) NOT NULL,\n`uuid` BINARY(16) NOT NULL,\n`token` VARBINARY(64) NOT NULL,\n`flags` BIT(8) NOT NULL);",
+		PostgresResult: "CREATE TABLE \"numeric_types\" (\n\"price\" DECIMAL(10,2) NOT NULL,\n\"ratio\" NUMERIC(5) NOT NULL,\n\"score\" FLOAT(53) NOT NULL,\n\"uuid\" BYTEA NOT NULL,\n\"token\" BYTEA NOT NULL,\n\"flags\" BIT(8) NOT NULL);",
+	},
+	{
+		Table: "enums",
+		Create: func(t *Table) {
+			t.Enum("status", Values("active", "it's done"))
+			t.Column("mood", Enum("happy", "sad")).EnumType("mood").Null()
+		},
+		SqliteResult:   "CREATE TABLE `enums` (\n'status' TEXT NOT NULL CHECK (`status` IN ('active', 'it''s done')),\n'mood' TEXT NULL CHECK (`mood` IN ('happy', 'sad')));",
+		MysqlResult:    "CREATE TABLE `enums` (\n`status` ENUM('active', 'it''s done') NOT NULL,\n`mood` ENUM('happy', 'sad') NULL);",
+		PostgresResult: "CREATE TYPE \"enums_status\" AS ENUM ('active', 'it''s done');CREATE TYPE \"mood\" AS ENUM ('happy', 'sad');CREATE TABLE \"enums\" (\n\"status\" \"enums_status\" NOT NULL,\n\"mood\" \"mood\" NULL);",
+	},
+	{
+		Table: "defaults",
+		Create: func(t *Table) {
+			t.String("name").Default(`it's a \`)
+			t.Boolean("active").Default(1)
+			t.Text("slug").Default(Expr("lower('x')"))
+			t.VarBinary("token").Default([]byte{0xde, 0xad})
+			t.Json("meta").Default(map[string]int{"a": 1})
+		},
+		SqliteResult:   "CREATE TABLE `defaults` (\n'name' TEXT NOT NULL DEFAULT 'it''s a \\',\n'active' INTEGER NOT NULL DEFAULT TRUE,\n'slug' TEXT NOT NULL DEFAULT (lower('x')),\n'token' BLOB NOT NULL DEFAULT X'dead',\n'meta' TEXT NOT NULL DEFAULT '{\"a\":1}');",
+		MysqlResult:    "CREATE TABLE `defaults` (\n`name` VARCHAR(255) NOT NULL DEFAULT 'it''s a \\\\',\n`active` BOOLEAN NOT NULL DEFAULT TRUE,\n`slug` TEXT NOT NULL DEFAULT (lower('x')),\n`token` VARBINARY(255) NOT NULL DEFAULT X'dead',\n`meta` JSON NOT NULL DEFAULT ('{\"a\":1}'));",
+		PostgresResult: "CREATE TABLE \"defaults\" (\n\"name\" VARCHAR(255) NOT NULL DEFAULT 'it''s a \\',\n\"active\" BOOLEAN NOT NULL DEFAULT TRUE,\n\"slug\" TEXT NOT NULL DEFAULT (lower('x')),\n\"token\" BYTEA NOT NULL DEFAULT '\\xdead',\n\"meta\" JSONB NOT NULL DEFAULT '{\"a\":1}');",
+	},
+	{
+		Table: "comments",
+		Create: func(t *Table) {
+			t.Comment("Registered users")
+			t.String("email").Comment("Login */ address")
+			t.Integer("age").Comment("Years, it's rounded")
+		},
+		SqliteResult:   "CREATE TABLE `comments` ( /* Registered users */\n'email' TEXT NOT NULL /* Login * / address */,\n'age' INTEGER NOT NULL /* Years, it's rounded */);",
+		MysqlResult:    "CREATE TABLE `comments` (\n`email` VARCHAR(255) NOT NULL COMMENT 'Login */ address',\n`age` INTEGER NOT NULL COMMENT 'Years, it''s rounded') COMMENT='Registered users';",
+		PostgresResult: "CREATE TABLE \"comments\" (\n\"email\" VARCHAR(255) NOT NULL,\n\"age\" INTEGER NOT NULL);COMMENT ON TABLE \"comments\" IS 'Registered users';COMMENT ON COLUMN \"comments\".\"email\" IS 'Login */ address';COMMENT ON COLUMN \"comments\".\"age\" IS 'Years, it''s rounded';",
+	},
+	{
+		Table: "table_options",
+		Create: func(t *Table) {
+			t.Primary("id")
+			t.Decimal("price", 10, 2)
+			t.Engine("InnoDB")
+			t.Charset("utf8mb4")
+			t.Collate("utf8mb4_unicode_ci")
+			t.RowFormat("DYNAMIC")
+			t.Strict()
+			t.WithoutRowid()
+			t.Unlogged()
+			t.With("fillfactor", 70)
+			t.Tablespace("fast")
+		},
+		SqliteResult:   "CREATE TABLE `table_options` (\n'id' INTEGER PRIMARY KEY,\n'price' ANY NOT NULL) STRICT, WITHOUT ROWID;",
+		MysqlResult:    "CREATE TABLE `table_options` (\n`id` INTEGER PRIMARY KEY,\n`price` DECIMAL(10,2) NOT NULL) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci ROW_FORMAT=DYNAMIC;",
+		PostgresResult: "CREATE UNLOGGED TABLE \"table_options\" (\n\"id\" INTEGER PRIMARY KEY,\n\"price\" DECIMAL(10,2) NOT NULL) WITH (fillfactor = 70) TABLESPACE \"fast\";",
+	},
+	{
+		Table: "collations",
+		Create: func(t *Table) {
+			t.String("username").Charset("utf8mb4").
+				Collate(driver.TypeSqlite3, "NOCASE").
+				Collate(driver.TypeMysql, "utf8mb4_unicode_ci").
+				Collate(driver.TypePostgres, "und-x-icu")
+			t.Text("bio", Collate(driver.TypePostgres, "C"))
+		},
+		SqliteResult:   "CREATE TABLE `collations` (\n'username' TEXT COLLATE NOCASE NOT NULL,\n'bio' TEXT NOT NULL);",
+		MysqlResult:    "CREATE TABLE `collations` (\n`username` VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,\n`bio` TEXT NOT NULL);",
+		PostgresResult: "CREATE TABLE \"collations\" (\n\"username\" VARCHAR(255) COLLATE \"und-x-icu\" NOT NULL,\n\"bio\" TEXT COLLATE \"C\" NOT NULL);",
 	},
 }
 
-func TestSqliteCreate(t *testing.T) {
+func testCreate(t *testing.T, driverType driver.Type, result func(s testStatement) string) {
 	for i, s := range createStatements {
+		expected := result(s)
+		if expected == "" {
+			continue
+		}
 		t.Run(fmt.Sprintf("Create '%s' - %d", s.Table, i), func(t *testing.T) {
-			schema := New(driver.TypeSqlite3, "test")
+			schema := New(driverType, "test")
 			var table *Table
 			schema.Create(s.Table, func(t *Table) {
 				table = t
 				s.Create(t)
 			})
 			t.Logf("Create '%s' - %d", s.Table, i)
-			statements := table.tableDef.Statements()
+			statements, err := table.tableDef.Statements()
+			if err != nil {
+				t.Fatal(err)
+			}
 			sql := strings.Join(statements, ";") + ";"
-			if !sqlStatementsAreEqual(s.SqliteResult, sql) {
-				t.Errorf("Expected \n%s\n but got \n%s\n", strings.TrimSpace(s.SqliteResult), strings.TrimSpace(sql))
+			if !sqlStatementsAreEqual(expected, sql) {
+				t.Errorf("Expected \n%s\n but got \n%s\n", strings.TrimSpace(expected), strings.TrimSpace(sql))
 			}
 		})
 	}
 }
 
+func TestSqliteCreate(t *testing.T) {
+	testCreate(t, driver.TypeSqlite3, func(s testStatement) string { return s.SqliteResult })
+}
+
+func TestMysqlCreate(t *testing.T) {
+	testCreate(t, driver.TypeMysql, func(s testStatement) string { return s.MysqlResult })
+}
+
+func TestPostgresCreate(t *testing.T) {
+	testCreate(t, driver.TypePostgres, func(s testStatement) string { return s.PostgresResult })
+}
+
 var alterStatements = []testStatement{
 	{
 		Table: "column_rename",
 		Alter: func(t *Table) {
 			t.Column("id").Name("new_id")
 		},
-		SqliteResult: "ALTER TABLE `column_rename` RENAME COLUMN `id` TO `new_id`;",
+		SqliteResult:   "ALTER TABLE `column_rename` RENAME COLUMN `id` TO `new_id`;",
+		MysqlResult:    "ALTER TABLE `column_rename` RENAME COLUMN `id` TO `new_id`;",
+		PostgresResult: "ALTER TABLE \"column_rename\" RENAME COLUMN \"id\" TO \"new_id\";",
+	},
+	{
+		Table: "add_column",
+		Alter: func(t *Table) {
+			t.Integer("user_id").Null().References("user", "id").OnDelete(SET_NULL{})
+		},
+		SqliteResult:   "ALTER TABLE `add_column` ADD COLUMN 'user_id' INTEGER NULL CONSTRAINT `fk_add_column_user_id` REFERENCES `user`('id') ON DELETE SET NULL;",
+		MysqlResult:    "ALTER TABLE `add_column` ADD COLUMN `user_id` INTEGER NULL;ALTER TABLE `add_column` ADD CONSTRAINT `fk_add_column_user_id` FOREIGN KEY (`user_id`) REFERENCES `user`(`id`) ON DELETE SET NULL;",
+		PostgresResult: "ALTER TABLE \"add_column\" ADD COLUMN \"user_id\" INTEGER NULL;ALTER TABLE \"add_column\" ADD CONSTRAINT \"fk_add_column_user_id\" FOREIGN KEY (\"user_id\") REFERENCES \"user\"(\"id\") ON DELETE SET NULL;",
+	},
+	{
+		Table: "add_composite_foreign_key",
+		Alter: func(t *Table) {
+			t.Foreign("user_id", "study_id").References("membership", "user_id", "study_id").Name("fk_membership")
+		},
+		SqliteResult:   "-- rebuild `add_composite_foreign_key`\n-- ADD CONSTRAINT `fk_membership` FOREIGN KEY ('user_id', 'study_id') REFERENCES `membership`('user_id', 'study_id');",
+		MysqlResult:    "ALTER TABLE `add_composite_foreign_key` ADD CONSTRAINT `fk_membership` FOREIGN KEY (`user_id`, `study_id`) REFERENCES `membership`(`user_id`, `study_id`);",
+		PostgresResult: "ALTER TABLE \"add_composite_foreign_key\" ADD CONSTRAINT \"fk_membership\" FOREIGN KEY (\"user_id\", \"study_id\") REFERENCES \"membership\"(\"user_id\", \"study_id\");",
+	},
+	{
+		Table: "checks",
+		Alter: func(t *Table) {
+			t.DropCheck("chk_status")
+			t.Check("age >= 0").Name("chk_age")
+		},
+		SqliteResult:   "-- rebuild `checks`\n-- DROP CONSTRAINT `chk_status`\n-- ADD CONSTRAINT `chk_age` CHECK (age >= 0);",
+		MysqlResult:    "ALTER TABLE `checks` DROP CHECK `chk_status`;ALTER TABLE `checks` ADD CONSTRAINT `chk_age` CHECK (age >= 0);",
+		PostgresResult: "ALTER TABLE \"checks\" DROP CONSTRAINT \"chk_status\";ALTER TABLE \"checks\" ADD CONSTRAINT \"chk_age\" CHECK (age >= 0);",
+	},
+	{
+		Table: "comment_alter",
+		Alter: func(t *Table) {
+			t.Comment("Registered users")
+			t.String("email").Comment("Login address")
+		},
+		MysqlResult:    "ALTER TABLE `comment_alter` COMMENT = 'Registered users';ALTER TABLE `comment_alter` ADD COLUMN `email` VARCHAR(255) NOT NULL COMMENT 'Login address';",
+		PostgresResult: "COMMENT ON TABLE \"comment_alter\" IS 'Registered users';ALTER TABLE \"comment_alter\" ADD COLUMN \"email\" VARCHAR(255) NOT NULL;COMMENT ON COLUMN \"comment_alter\".\"email\" IS 'Login address';",
+	},
+	{
+		Table: "collation_alter",
+		Alter: func(t *Table) {
+			t.String("username").Charset("utf8mb4").
+				Collate(driver.TypeSqlite3, "NOCASE").
+				Collate(driver.TypeMysql, "utf8mb4_unicode_ci")
+		},
+		SqliteResult:   "ALTER TABLE `collation_alter` ADD COLUMN 'username' TEXT COLLATE NOCASE NOT NULL;",
+		MysqlResult:    "ALTER TABLE `collation_alter` ADD COLUMN `username` VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL;",
+		PostgresResult: "ALTER TABLE \"collation_alter\" ADD COLUMN \"username\" VARCHAR(255) NOT NULL;",
+	},
+	{
+		Table: "collation_modify",
+		Alter: func(t *Table) {
+			t.String("username").Modify().
+				Collate(driver.TypeSqlite3, "NOCASE").
+				Collate(driver.TypeMysql, "utf8mb4_unicode_ci").
+				Collate(driver.TypePostgres, "und-x-icu")
+		},
+		SqliteResult:   "-- rebuild `collation_modify`\n-- MODIFY COLUMN 'username' TEXT COLLATE NOCASE NOT NULL;",
+		MysqlResult:    "ALTER TABLE `collation_modify` MODIFY COLUMN `username` VARCHAR(255) COLLATE utf8mb4_unicode_ci NOT NULL;",
+		PostgresResult: "ALTER TABLE \"collation_modify\" ALTER COLUMN \"username\" TYPE VARCHAR(255) COLLATE \"und-x-icu\", ALTER COLUMN \"username\" SET NOT NULL, ALTER COLUMN \"username\" DROP DEFAULT;",
+	},
+	{
+		Table: "default_modify",
+		Alter: func(t *Table) {
+			t.Integer("visits").Null().Default(0).Modify()
+			t.Enum("mood", Values("happy", "sad")).EnumType("mood").Modify()
+		},
+		SqliteResult:   "-- rebuild `default_modify`\n-- MODIFY COLUMN 'visits' INTEGER NULL DEFAULT 0\n-- MODIFY COLUMN 'mood' TEXT NOT NULL CHECK (`mood` IN ('happy', 'sad'));",
+		MysqlResult:    "ALTER TABLE `default_modify` MODIFY COLUMN `visits` INTEGER NULL DEFAULT 0;ALTER TABLE `default_modify` MODIFY COLUMN `mood` ENUM('happy', 'sad') NOT NULL;",
+		PostgresResult: "ALTER TABLE \"default_modify\" ALTER COLUMN \"visits\" TYPE INTEGER, ALTER COLUMN \"visits\" DROP NOT NULL, ALTER COLUMN \"visits\" SET DEFAULT 0;ALTER TABLE \"default_modify\" ALTER COLUMN \"mood\" TYPE \"mood\", ALTER COLUMN \"mood\" SET NOT NULL, ALTER COLUMN \"mood\" DROP DEFAULT;",
 	},
 }
 
-func TestSqliteAlter(t *testing.T) {
+func testAlter(t *testing.T, driverType driver.Type, result func(s testStatement) string) {
 	for i, s := range alterStatements {
-		schema := New(driver.TypeSqlite3, "test")
+		expected := result(s)
+		if expected == "" {
+			continue
+		}
+		schema := New(driverType, "test")
 		var table *Table
 		schema.Table(s.Table, func(t *Table) {
 			table = t
 			s.Alter(t)
 		})
 		t.Logf("Alter '%s' - %d", s.Table, i)
-		statements := table.tableDef.Statements()
+		statements, err := table.tableDef.Statements()
+		if err != nil {
+			t.Errorf("Alter '%s' - %d: %s", s.Table, i, err)
+			continue
+		}
 		sql := strings.Join(statements, ";") + ";"
-		if !sqlStatementsAreEqual(s.SqliteResult, sql) {
-			t.Errorf("Expected \n%s\n but got \n%s\n", strings.TrimSpace(s.SqliteResult), strings.TrimSpace(sql))
+		if !sqlStatementsAreEqual(expected, sql) {
+			t.Errorf("Expected \n%s\n but got \n%s\n", strings.TrimSpace(expected), strings.TrimSpace(sql))
 		}
 	}
 }
+
+func TestSqliteAlter(t *testing.T) {
+	testAlter(t, driver.TypeSqlite3, func(s testStatement) string { return s.SqliteResult })
+}
+
+func TestMysqlAlter(t *testing.T) {
+	testAlter(t, driver.TypeMysql, func(s testStatement) string { return s.MysqlResult })
+}
+
+func TestPostgresAlter(t *testing.T) {
+	testAlter(t, driver.TypePostgres, func(s testStatement) string { return s.PostgresResult })
+}
+
+func TestUnsupportedIndexOptions(t *testing.T) {
+	cases := map[driver.Type]func(i *indexBuilder){
+		driver.TypeSqlite3:  func(i *indexBuilder) { i.Using(IndexBtree) },
+		driver.TypeMysql:    func(i *indexBuilder) { i.Using(IndexGin) },
+		driver.TypePostgres: func(i *indexBuilder) { i.Prefix("name", 10) },
+	}
+	for driverType, fn := range cases {
+		t.Run(string(driverType), func(t *testing.T) {
+			schema := New(driverType, "test")
+			schema.Create("unsupported", func(t *Table) {
+				t.String("name")
+				fn(t.Index("name"))
+			})
+			if _, err := schema.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Expected unsupported index option to fail for %s but got %v", driverType, err)
+			}
+		})
+	}
+}
+
+func TestUnsupportedMysqlIndexKinds(t *testing.T) {
+	cases := map[string]func(i *indexBuilder){
+		"unique fulltext":  func(i *indexBuilder) { i.Unique().Fulltext() },
+		"unique spatial":   func(i *indexBuilder) { i.Unique().Spatial() },
+		"fulltext spatial": func(i *indexBuilder) { i.Fulltext().Spatial() },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			schema := New(driver.TypeMysql, "test")
+			schema.Create("unsupported", func(t *Table) {
+				t.String("name")
+				fn(t.Index("name"))
+			})
+			if _, err := schema.Schema.Statements(); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Expected %s index to fail but got %v", name, err)
+			}
+		})
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	cases := map[string]func(s *Schema){
+		"empty table": func(s *Schema) {
+			s.Create("empty", func(t *Table) {})
+		},
+		"unknown index column": func(s *Schema) {
+			s.Create("user", func(t *Table) {
+				t.String("name")
+				t.Index("email")
+			})
+		},
+		"unknown order column": func(s *Schema) {
+			s.Create("user", func(t *Table) {
+				t.String("name")
+				t.Index("name").Desc("email")
+			})
+		},
+		"undefined reference": func(s *Schema) {
+			s.Create("user", func(t *Table) {
+				t.Primary("id")
+			})
+			s.Create("post", func(t *Table) {
+				t.Integer("user_id").References("user", "uid")
+			})
+		},
+		"action without reference": func(s *Schema) {
+			s.Create("post", func(t *Table) {
+				t.Integer("user_id").OnDelete(CASCADE{})
+			})
+		},
+		"foreign without reference": func(s *Schema) {
+			s.Create("post", func(t *Table) {
+				t.Integer("user_id")
+				t.Foreign("user_id")
+			})
+		},
+		"generated column with default": func(s *Schema) {
+			s.Create("visit", func(t *Table) {
+				t.Integer("a")
+				t.Integer("total").Generated("a * 2").Default(0)
+			})
+		},
+		"modified column in new table": func(s *Schema) {
+			s.Create("user", func(t *Table) {
+				t.String("name").Modify()
+			})
+		},
+		"stored generated column added": func(s *Schema) {
+			s.Table("visit", func(t *Table) {
+				t.Integer("total").Generated("a * 2").Stored()
+			})
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			schema := New(driver.TypeSqlite3, "test")
+			fn(schema)
+			if _, err := schema.Schema.Statements(); err == nil {
+				t.Errorf("Expected %s to fail", name)
+			}
+		})
+	}
+	schema := New(driver.Type("oracle"), "test")
+	schema.Create("user", func(t *Table) {
+		t.Primary("id")
+	})
+	if _, err := schema.Schema.Statements(); err == nil {
+		t.Error("Expected unknown driver to fail")
+	}
+}
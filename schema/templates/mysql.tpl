@@ -0,0 +1,266 @@
+{{ define "create_table" }}
+CREATE TABLE {{- if .IfNotExists}} IF NOT EXISTS {{ end }} `{{.Name}}` (
+  {{- range $i, $col := .Columns -}}
+    {{- if $i}},{{end -}}
+    {{- template "column" $col -}}
+  {{- end}}
+  
+  {{- if gt .NumPrimary 1 -}},
+PRIMARY KEY (
+    {{- range $i, $col := .PrimaryColumns -}}
+      {{- if $i}}, {{end -}}
+      `{{$col.Name}}`
+    {{- end -}}
+    )
+  {{- end -}}
+
+  {{- range .InlineForeigns -}},
+    {{ template "foreign" . }}
+  {{- end }}
+  {{- range .Checks -}},
+    {{ template "check" . }}
+  {{- end }}
+)
+{{- with .Engine }} ENGINE={{ . }}{{ end }}
+{{- with .Charset }} DEFAULT CHARSET={{ . }}{{ end }}
+{{- with .Collation }} COLLATE={{ . }}{{ end }}
+{{- with .RowFormat }} ROW_FORMAT={{ . }}{{ end }}
+{{- with .Comment }} COMMENT={{ Quote . }}{{ end }}
+{{- with .Partitioning }} PARTITION BY {{ .Method }}{{ if ne .Method "HASH" }} COLUMNS{{ end }}(
+  {{- range $i, $col := .Columns }}{{ if $i }}, {{ end }}`{{ $col }}`{{ end -}}
+)
+{{- with $.Partitions }} ({{ range $i, $p := . }}{{ if $i }}, {{ end }}{{ template "partition" $p }}{{ end }}){{ end }}
+{{- end }}
+{{ end }}
+
+{{ define "column" }}
+`{{.Name}}` {{GetType .}}
+{{- with .Charset }} CHARACTER SET {{ . }}{{ end }}
+{{- with .GetCollation }} COLLATE {{ . }}{{ end }}
+{{- if .GeneratedAs }} GENERATED ALWAYS AS ({{ .GeneratedAs }}){{ if .IsStored }} STORED{{ else }} VIRTUAL{{ end }}{{ end }}
+{{- if not .SoloPrimary }}{{ if not .IsNull }} NOT NULL{{ else }} NULL{{- end -}}{{- end -}}
+{{- if .IsAutoincrement }} AUTO_INCREMENT{{- end -}}
+{{- if .SoloPrimary }} PRIMARY KEY{{- end -}}
+{{- if .IsUnique }} UNIQUE{{- end -}}
+{{- GetDefault .Kind .DefaultVal -}}
+{{- with .Comment }} COMMENT {{ Quote . }}{{ end -}}
+{{- range .Checks }} {{ template "check" . }}{{ end -}}
+{{ end }}
+
+{{ define "create_index" }}
+{{- if .Where }}{{ unsupported "partial indices" }}{{ end -}}
+{{- if .Include }}{{ unsupported "covering indices" }}{{ end -}}
+{{- if .Params }}{{ unsupported "index storage parameters" }}{{ end -}}
+{{- if or (and .Unique (or .Fulltext .Spatial)) (and .Fulltext .Spatial) }}{{ unsupported "combined UNIQUE, FULLTEXT and SPATIAL indices" }}{{ end -}}
+CREATE{{ if .Unique }} UNIQUE{{ else if .Fulltext }} FULLTEXT{{ else if .Spatial }} SPATIAL{{- end }} INDEX `{{ .GetName }}`
+{{- with .Using }} USING {{ . }}{{ end }} ON `{{.Table.Name}}`(
+  {{- range $i, $col := .Columns -}}
+  {{- if $i }}, {{ end -}}
+  {{- if $col.Expr }}({{ $col.Expr }}){{ else }}`{{- $col.Name -}}`{{ end }}
+  {{- with $col.Length }}({{ . }}){{ end }}
+  {{- if $col.Collate }}{{ unsupported "index column collations" }}{{ end }}
+  {{- with $col.Order }} {{ . }}{{ end }}
+  {{- end -}}
+)
+{{ end }}
+
+{{ define "foreign" -}}
+CONSTRAINT `{{ .GetName }}` FOREIGN KEY (
+  {{- range $i, $col := .Columns }}{{ if $i }}, {{ end }}`{{ $col }}`{{ end -}}
+) REFERENCES `{{ .RefTable }}`(
+  {{- range $i, $col := .RefColumns }}{{ if $i }}, {{ end }}`{{ $col }}`{{ end -}}
+)
+{{- with .OnDelete }} ON DELETE {{ Action . }}{{ end }}
+{{- with .OnUpdate }} ON UPDATE {{ Action . }}{{ end }}
+{{- end }}
+
+{{ define "add_foreign" -}}
+ALTER TABLE `{{ .Table.Name }}` ADD {{ template "foreign" . }}
+{{- end }}
+
+{{ define "add_column" -}}
+ALTER TABLE `{{ .Table.Name }}` ADD COLUMN {{ template "column" . }}
+{{- end }}
+
+{{ define "modify_column" -}}
+ALTER TABLE `{{ .Table.Name }}` MODIFY COLUMN {{ template "column" . }}
+{{- end }}
+
+{{ define "rename_column" -}}
+ALTER TABLE `{{ .Table.Name }}` RENAME COLUMN `{{ .OriginalName }}` TO `{{ .Name }}`
+{{- end }}
+
+{{ define "check" -}}
+{{ with .Name }}CONSTRAINT `{{ . }}` {{ end }}CHECK ({{ .Expr }})
+{{- end }}
+
+{{ define "add_check" -}}
+ALTER TABLE `{{ .Table.Name }}` ADD {{ template "check" . }}
+{{- end }}
+
+{{ define "drop_check" -}}
+ALTER TABLE `{{ .Table.Name }}` DROP CHECK `{{ .Name }}`
+{{- end }}
+
+{{ define "create_view" -}}
+{{ if .Materialized }}{{ unsupported "materialized views" }}{{ end -}}
+CREATE{{- if .OrReplace }} OR REPLACE{{ end }} VIEW `{{ .Name }}` AS {{ .Query }}
+{{- end }}
+
+{{ define "refresh_view" -}}
+{{ unsupported "materialized views" }}
+{{- end }}
+
+{{ define "drop_view" -}}
+{{ if .Materialized }}{{ unsupported "materialized views" }}{{ end -}}
+DROP VIEW {{- if .IfExists }} IF EXISTS{{ end }} `{{ .Name }}`
+{{- end }}
+
+{{ define "create_trigger" -}}
+{{ if eq .Timing "INSTEAD OF" }}{{ unsupported "INSTEAD OF triggers" }}{{ end -}}
+CREATE TRIGGER `{{ .Name }}` {{ .Timing }} {{ .Event }} ON `{{ .Table }}` FOR EACH ROW
+BEGIN
+{{ .Body }}
+END
+{{- end }}
+
+{{ define "drop_trigger" -}}
+DROP TRIGGER `{{ .Name }}`
+{{- end }}
+
+{{ define "create_enum" -}}
+{{ unsupported "enum types" }}
+{{- end }}
+
+{{ define "add_enum_value" -}}
+{{ unsupported "enum types" }}
+{{- end }}
+
+{{ define "drop_enum" -}}
+{{ unsupported "enum types" }}
+{{- end }}
+
+{{ define "create_sequence" -}}
+{{ unsupported "sequences" }}
+{{- end }}
+
+{{ define "restart_sequence" -}}
+{{ unsupported "sequences" }}
+{{- end }}
+
+{{ define "drop_sequence" -}}
+{{ unsupported "sequences" }}
+{{- end }}
+
+{{ define "create_extension" -}}
+{{ unsupported "extensions" }}
+{{- end }}
+
+{{ define "drop_extension" -}}
+{{ unsupported "extensions" }}
+{{- end }}
+
+{{ define "create_type" -}}
+{{ unsupported "composite types" }}
+{{- end }}
+
+{{ define "drop_type" -}}
+{{ unsupported "composite types" }}
+{{- end }}
+
+{{ define "create_domain" -}}
+{{ unsupported "domains" }}
+{{- end }}
+
+{{ define "drop_domain" -}}
+{{ unsupported "domains" }}
+{{- end }}
+
+{{ define "comment_table" -}}
+ALTER TABLE `{{ .Name }}` COMMENT = {{ Quote .Comment }}
+{{- end }}
+
+{{ define "drop_column" -}}
+ALTER TABLE `{{ .Table.Name }}` DROP COLUMN `{{ .Name }}`
+{{- end }}
+
+{{ define "drop_index" -}}
+{{ if not .Table }}{{ unsupported "indices dropped without their table" }}{{ end -}}
+DROP INDEX `{{ .Name }}` ON `{{ .Table.Name }}`
+{{- end }}
+
+{{ define "drop_foreign" -}}
+ALTER TABLE `{{ .Table.Name }}` DROP FOREIGN KEY `{{ .Name }}`
+{{- end }}
+
+{{ define "drop_tables" -}}
+DROP TABLE {{ range $i, $t := . }}{{ if $i }}, {{ end }}`{{ $t }}`{{ end }}
+{{- end }}
+
+{{ define "create_search" -}}
+CREATE FULLTEXT INDEX `{{ .Name }}` ON `{{ .Table.Name }}` (
+{{- range $i, $col := .Columns }}{{ if $i }}, {{ end }}`{{ $col }}`{{ end -}}
+)
+{{- end }}
+
+{{ define "drop_search" -}}
+DROP INDEX `{{ .Name }}` ON `{{ .Table.Name }}`
+{{- end }}
+
+{{ define "partition" -}}
+PARTITION `{{ .Name }}`
+{{- if .IsDefault }} VALUES LESS THAN (MAXVALUE)
+{{- else if .In }} VALUES IN ({{ .InValues }})
+{{- else if not .Modulus }} VALUES LESS THAN ({{ .ToValue }})
+{{- end }}
+{{- end }}
+
+{{ define "create_partition" -}}
+ALTER TABLE `{{ .Table }}` ADD PARTITION ({{ template "partition" . }})
+{{- end }}
+
+{{ define "attach_partition" -}}
+{{ unsupported "attached partitions" }}
+{{- end }}
+
+{{ define "detach_partition" -}}
+{{ unsupported "detached partitions" }}
+{{- end }}
+
+{{ define "drop_partition" -}}
+ALTER TABLE `{{ .Table }}` DROP PARTITION `{{ .Name }}`
+{{- end }}
+
+{{ define "enable_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "force_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "no_force_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "disable_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "create_policy" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "drop_policy" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "grant" -}}
+{{ if ne .Object "TABLE" }}{{ unsupported "sequence grants" }}{{ end -}}
+GRANT {{ .GetPrivileges }} ON `{{ .Name }}` TO {{ QuoteRole .Role }}
+{{- end }}
+
+{{ define "revoke" -}}
+{{ if ne .Object "TABLE" }}{{ unsupported "sequence grants" }}{{ end -}}
+REVOKE {{ .GetPrivileges }} ON `{{ .Name }}` FROM {{ QuoteRole .Role }}
+{{- end }}
@@ -0,0 +1,287 @@
+{{ define "create_table" }}
+CREATE {{- if .Unlogged }} UNLOGGED{{ end }} TABLE {{- if .IfNotExists}} IF NOT EXISTS {{ end }} "{{.Name}}" (
+  {{- range $i, $col := .Columns -}}
+    {{- if $i}},{{end -}}
+    {{- template "column" $col -}}
+  {{- end}}
+  
+  {{- if gt .NumPrimary 1 -}},
+PRIMARY KEY (
+    {{- range $i, $col := .PrimaryColumns -}}
+      {{- if $i}}, {{end -}}
+      "{{$col.Name}}"
+    {{- end -}}
+    )
+  {{- end -}}
+
+  {{- range .InlineForeigns -}},
+    {{ template "foreign" . }}
+  {{- end }}
+  {{- range .Checks -}},
+    {{ template "check" . }}
+  {{- end }}
+)
+{{- with .Partitioning }} PARTITION BY {{ .Method }} ({{ range $i, $col := .Columns }}{{ if $i }}, {{ end }}"{{ $col }}"{{ end }}){{ end }}
+{{- with .Params }} WITH ({{ range $i, $p := . }}{{ if $i }}, {{ end }}{{ $p.Name }} = {{ $p.Value }}{{ end }}){{ end }}
+{{- with .Tablespace }} TABLESPACE "{{ . }}"{{ end }}
+{{ end }}
+
+{{ define "column" }}
+"{{.Name}}" {{GetType .}}
+{{- with .GetCollation }} COLLATE "{{ . }}"{{ end }}
+{{- with .GeneratedAs }} GENERATED ALWAYS AS ({{ . }}) STORED{{ end }}
+{{- if .IsAutoincrement }} GENERATED BY DEFAULT AS IDENTITY{{- end -}}
+{{- if .SoloPrimary }} PRIMARY KEY{{- end -}}
+{{- if not .SoloPrimary }}{{ if not .IsNull }} NOT NULL{{ else }} NULL{{- end -}}{{- end -}}
+{{- if .IsUnique }} UNIQUE{{- end -}}
+{{- GetDefault .Kind .DefaultVal -}}
+{{- range .Checks }} {{ template "check" . }}{{ end -}}
+{{ end }}
+
+{{ define "create_index" }}
+{{- if or .Fulltext .Spatial }}{{ unsupported "FULLTEXT and SPATIAL indices" }}{{ end -}}
+CREATE{{ if .Unique }} UNIQUE{{- end }} INDEX{{ if .IfNotExists }} IF NOT EXISTS{{ end }} "{{ .GetName }}" ON "{{.Table.Name}}"
+{{- with .Using }} USING {{ . }}{{ end }}(
+  {{- range $i, $col := .Columns -}}
+  {{- if $i }}, {{ end -}}
+  {{- if $col.Expr }}({{ $col.Expr }}){{ else }}"{{- $col.Name -}}"{{ end }}
+  {{- if $col.Length }}{{ unsupported "index prefix lengths" }}{{ end }}
+  {{- with $col.Collate }} COLLATE "{{ . }}"{{ end }}
+  {{- with $col.Order }} {{ . }}{{ end }}
+  {{- end -}}
+)
+{{- with .Include }} INCLUDE ({{ range $i, $col := . }}{{ if $i }}, {{ end }}"{{ $col }}"{{ end }}){{ end }}
+{{- with .Params }} WITH ({{ range $i, $p := . }}{{ if $i }}, {{ end }}{{ $p.Name }} = {{ $p.Value }}{{ end }}){{ end }}
+{{- with .Where }} WHERE {{ . }}{{ end }}
+{{ end }}
+
+{{ define "foreign" -}}
+CONSTRAINT "{{ .GetName }}" FOREIGN KEY (
+  {{- range $i, $col := .Columns }}{{ if $i }}, {{ end }}"{{ $col }}"{{ end -}}
+) REFERENCES "{{ .RefTable }}"(
+  {{- range $i, $col := .RefColumns }}{{ if $i }}, {{ end }}"{{ $col }}"{{ end -}}
+)
+{{- with .OnDelete }} ON DELETE {{ Action . }}{{ end }}
+{{- with .OnUpdate }} ON UPDATE {{ Action . }}{{ end }}
+{{- end }}
+
+{{ define "add_foreign" -}}
+ALTER TABLE "{{ .Table.Name }}" ADD {{ template "foreign" . }}
+{{- end }}
+
+{{ define "add_column" -}}
+ALTER TABLE "{{ .Table.Name }}" ADD COLUMN {{ template "column" . }}
+{{- end }}
+
+{{ define "modify_column" -}}
+ALTER TABLE "{{ .Table.Name }}" ALTER COLUMN "{{ .Name }}" TYPE {{ GetType . }}
+{{- with .GetCollation }} COLLATE "{{ . }}"{{ end }}, ALTER COLUMN "{{ .Name }}" {{ if .IsNull }}DROP{{ else }}SET{{ end }} NOT NULL
+{{- with GetDefault .Kind .DefaultVal }}, ALTER COLUMN "{{ $.Name }}" SET{{ . }}{{ else }}, ALTER COLUMN "{{ .Name }}" DROP DEFAULT{{ end }}
+{{- end }}
+
+{{ define "rename_column" -}}
+ALTER TABLE "{{ .Table.Name }}" RENAME COLUMN "{{ .OriginalName }}" TO "{{ .Name }}"
+{{- end }}
+
+{{ define "check" -}}
+{{ with .Name }}CONSTRAINT "{{ . }}" {{ end }}CHECK ({{ .Expr }})
+{{- end }}
+
+{{ define "add_check" -}}
+ALTER TABLE "{{ .Table.Name }}" ADD {{ template "check" . }}
+{{- end }}
+
+{{ define "drop_check" -}}
+ALTER TABLE "{{ .Table.Name }}" DROP CONSTRAINT "{{ .Name }}"
+{{- end }}
+
+{{ define "create_view" -}}
+CREATE {{- if .OrReplace }} OR REPLACE{{ end }}{{ if .Materialized }} MATERIALIZED{{ end }} VIEW "{{ .Name }}" AS {{ .Query }}
+{{- end }}
+
+{{ define "refresh_view" -}}
+REFRESH MATERIALIZED VIEW "{{ .Name }}"
+{{- end }}
+
+{{ define "drop_view" -}}
+DROP {{- if .Materialized }} MATERIALIZED{{ end }} VIEW {{- if .IfExists }} IF EXISTS{{ end }} "{{ .Name }}"
+{{- end }}
+
+{{ define "create_trigger_function" -}}
+CREATE OR REPLACE FUNCTION "{{ .FunctionName }}"() RETURNS trigger AS $$
+BEGIN
+{{ .Body }}
+RETURN {{ .ReturnRow }};
+END;
+$$ LANGUAGE plpgsql
+{{- end }}
+
+{{ define "create_trigger" -}}
+CREATE TRIGGER "{{ .Name }}" {{ .Timing }} {{ .Event }} ON "{{ .Table }}" FOR EACH ROW EXECUTE FUNCTION "{{ .FunctionName }}"()
+{{- end }}
+
+{{ define "drop_trigger" -}}
+DROP TRIGGER "{{ .Name }}" ON "{{ .Table }}"
+{{- end }}
+
+{{ define "drop_trigger_function" -}}
+DROP FUNCTION "{{ .FunctionName }}"()
+{{- end }}
+
+{{ define "create_enum" -}}
+CREATE TYPE "{{ .Name }}" AS ENUM ({{ QuoteAll .Values }})
+{{- end }}
+
+{{ define "add_enum_value" -}}
+ALTER TYPE "{{ .Name }}" ADD VALUE IF NOT EXISTS {{ QuoteAll .Values }}
+{{- end }}
+
+{{ define "drop_enum" -}}
+DROP TYPE "{{ .Name }}"
+{{- end }}
+
+{{ define "create_sequence" -}}
+CREATE SEQUENCE "{{ .Name }}"
+{{- with .Increment }} INCREMENT BY {{ . }}{{ end }}
+{{- with .MinValue }} MINVALUE {{ . }}{{ end }}
+{{- with .MaxValue }} MAXVALUE {{ . }}{{ end }}
+{{- with .Start }} START WITH {{ . }}{{ end }}
+{{- if .Cycle }} CYCLE{{ end }}
+{{- if .OwnedTable }} OWNED BY "{{ .OwnedTable }}"."{{ .OwnedColumn }}"{{ end }}
+{{- end }}
+
+{{ define "restart_sequence" -}}
+ALTER SEQUENCE "{{ .Name }}" RESTART WITH {{ .Restart }}
+{{- end }}
+
+{{ define "drop_sequence" -}}
+DROP SEQUENCE "{{ .Name }}"
+{{- end }}
+
+{{ define "create_extension" -}}
+CREATE EXTENSION IF NOT EXISTS "{{ .Name }}"
+{{- end }}
+
+{{ define "drop_extension" -}}
+DROP EXTENSION "{{ .Name }}"
+{{- end }}
+
+{{ define "create_type" -}}
+CREATE TYPE "{{ .Name }}" AS (
+{{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}"{{ $f.Name }}" {{ $f.Type }}{{ end -}}
+)
+{{- end }}
+
+{{ define "drop_type" -}}
+DROP TYPE "{{ .Name }}"
+{{- end }}
+
+{{ define "create_domain" -}}
+CREATE DOMAIN "{{ .Name }}" AS {{ .Type }}
+{{- with .Default }} DEFAULT {{ . }}{{ end }}
+{{- if .IsNotNull }} NOT NULL{{ end }}
+{{- range .Checks }} {{ template "check" . }}{{ end }}
+{{- end }}
+
+{{ define "drop_domain" -}}
+DROP DOMAIN "{{ .Name }}"
+{{- end }}
+
+{{ define "comment_table" -}}
+COMMENT ON TABLE "{{ .Name }}" IS {{ Quote .Comment }}
+{{- end }}
+
+{{ define "comment_column" -}}
+COMMENT ON COLUMN "{{ .Table.Name }}"."{{ .Name }}" IS {{ Quote .Comment }}
+{{- end }}
+
+{{ define "drop_column" -}}
+ALTER TABLE "{{ .Table.Name }}" DROP COLUMN "{{ .Name }}"
+{{- end }}
+
+{{ define "drop_index" -}}
+DROP INDEX "{{ .Name }}"
+{{- end }}
+
+{{ define "drop_foreign" -}}
+ALTER TABLE "{{ .Table.Name }}" DROP CONSTRAINT "{{ .Name }}"
+{{- end }}
+
+{{ define "drop_tables" -}}
+DROP TABLE {{ range $i, $t := . }}{{ if $i }}, {{ end }}"{{ $t }}"{{ end }}
+{{- end }}
+
+{{ define "add_search_column" -}}
+ALTER TABLE "{{ .Table.Name }}" ADD COLUMN "{{ .Name }}" tsvector GENERATED ALWAYS AS (to_tsvector({{ Quote .GetLanguage }},
+{{- range $i, $col := .Columns }}{{ if $i }} || ' ' ||{{ end }} coalesce("{{ $col }}", ''){{ end -}}
+)) STORED
+{{- end }}
+
+{{ define "create_search" -}}
+CREATE INDEX "idx_{{ .Table.Name }}_{{ .Name }}" ON "{{ .Table.Name }}" USING gin("{{ .Name }}")
+{{- end }}
+
+{{ define "drop_search" -}}
+ALTER TABLE "{{ .Table.Name }}" DROP COLUMN "{{ .Name }}"
+{{- end }}
+
+{{ define "partition_bound" -}}
+{{ if .IsDefault }}DEFAULT
+{{- else if .In }}FOR VALUES IN ({{ .InValues }})
+{{- else if .Modulus }}FOR VALUES WITH (MODULUS {{ .Modulus }}, REMAINDER {{ .Remainder }})
+{{- else }}FOR VALUES FROM ({{ .FromValue }}) TO ({{ .ToValue }})
+{{- end }}
+{{- end }}
+
+{{ define "create_partition" -}}
+CREATE TABLE "{{ .Name }}" PARTITION OF "{{ .Table }}" {{ template "partition_bound" . }}
+{{- end }}
+
+{{ define "attach_partition" -}}
+ALTER TABLE "{{ .Table }}" ATTACH PARTITION "{{ .Name }}" {{ template "partition_bound" . }}
+{{- end }}
+
+{{ define "detach_partition" -}}
+ALTER TABLE "{{ .Table }}" DETACH PARTITION "{{ .Name }}"
+{{- end }}
+
+{{ define "drop_partition" -}}
+DROP TABLE "{{ .Name }}"
+{{- end }}
+
+{{ define "enable_row_security" -}}
+ALTER TABLE "{{ .Name }}" ENABLE ROW LEVEL SECURITY
+{{- end }}
+
+{{ define "force_row_security" -}}
+ALTER TABLE "{{ .Name }}" FORCE ROW LEVEL SECURITY
+{{- end }}
+
+{{ define "no_force_row_security" -}}
+ALTER TABLE "{{ .Name }}" NO FORCE ROW LEVEL SECURITY
+{{- end }}
+
+{{ define "disable_row_security" -}}
+ALTER TABLE "{{ .Name }}" DISABLE ROW LEVEL SECURITY
+{{- end }}
+
+{{ define "create_policy" -}}
+CREATE POLICY "{{ .Name }}" ON "{{ .Table.Name }}"
+{{- if .Restrictive }} AS RESTRICTIVE{{ end }}
+{{- with .Command }} FOR {{ . }}{{ end }}
+{{- with .Roles }} TO {{ range $i, $role := . }}{{ if $i }}, {{ end }}{{ QuoteRole $role }}{{ end }}{{ end }}
+{{- with .Using }} USING ({{ . }}){{ end }}
+{{- with .WithCheck }} WITH CHECK ({{ . }}){{ end }}
+{{- end }}
+
+{{ define "drop_policy" -}}
+DROP POLICY "{{ .Name }}" ON "{{ .Table.Name }}"
+{{- end }}
+
+{{ define "grant" -}}
+GRANT {{ .GetPrivileges }} ON {{ .Object }} "{{ .Name }}" TO {{ QuoteRole .Role }}
+{{- end }}
+
+{{ define "revoke" -}}
+REVOKE {{ .GetPrivileges }} ON {{ .Object }} "{{ .Name }}" FROM {{ QuoteRole .Role }}
+{{- end }}
@@ -1,5 +1,6 @@
 {{ define "create_table" }}
-CREATE TABLE {{- if .IfNotExists}} IF NOT EXISTS {{ end }} `{{.Name}}` (
+{{- if .Partitioning }}{{ unsupported "partitioned tables" }}{{ end }}
+CREATE TABLE {{- if .IfNotExists}} IF NOT EXISTS {{ end }} `{{.Name}}` ({{ with .Comment }} {{ BlockComment . }}{{ end }}
   {{- range $i, $col := .Columns -}}
     {{- if $i}},{{end -}}
     {{- template "column" $col -}}
@@ -7,38 +8,245 @@ CREATE TABLE {{- if .IfNotExists}} IF NOT EXISTS {{ end }} `{{.Name}}` (
   
   {{- if gt .NumPrimary 1 -}},
 PRIMARY KEY (
-    {{- range $i, $col := .Columns -}}
-      {{- if $col.IsPrimary -}}
-        {{- if $i}}, {{end -}}
-        '{{$col.Name}}'
-      {{- end -}}
-    {{end -}}
+    {{- range $i, $col := .PrimaryColumns -}}
+      {{- if $i}}, {{end -}}
+      '{{$col.Name}}'
+    {{- end -}}
     )
   {{- end -}}
 
-  {{- range $i, $col := .Columns -}}
-    {{ if $col.ReferenceTo -}},
-    FOREIGN KEY ('{{ $col.Name }}') REFERENCES `{{$col.ReferenceTo.Table}}`('{{ $col.ReferenceTo.Column }}')
-    {{ end -}}
-  {{- end -}}
+  {{- range .InlineForeigns -}},
+    {{ template "foreign" . }}
+  {{- end }}
+  {{- range .Checks -}},
+    {{ template "check" . }}
+  {{- end }}
 )
+{{- if .Strict }} STRICT{{ if .WithoutRowid }},{{ end }}{{ end }}
+{{- if .WithoutRowid }} WITHOUT ROWID{{ end }}
 {{ end }}
 
 {{ define "column" }}
-'{{.Name}}' {{GetType .Kind .KindLen}}
+'{{.Name}}' {{GetType .}}
+{{- with .GetCollation }} COLLATE {{ . }}{{ end }}
+{{- if .GeneratedAs }} GENERATED ALWAYS AS ({{ .GeneratedAs }}){{ if .IsStored }} STORED{{ else }} VIRTUAL{{ end }}{{ end }}
 {{- if .SoloPrimary }} PRIMARY KEY{{- end -}}
 {{- if .IsAutoincrement }} AUTOINCREMENT{{- end -}}
 {{- if not .SoloPrimary }}{{ if not .IsNull }} NOT NULL{{ else }} NULL{{- end -}}{{- end -}}
 {{- if .IsUnique }} UNIQUE{{- end -}}
 {{- GetDefault .Kind .DefaultVal -}}
+{{- with .Comment }} {{ BlockComment . }}{{ end -}}
+{{- if .IsEnum }} CHECK (`{{ .Name }}` IN ({{ QuoteAll .EnumValues }})){{ end -}}
+{{- range .Checks }} {{ template "check" . }}{{ end -}}
 {{ end }}
 
 {{ define "create_index" }}
+{{- if .Method }}{{ unsupported "index methods" }}{{ end -}}
+{{- if .Include }}{{ unsupported "covering indices" }}{{ end -}}
+{{- if or .Fulltext .Spatial }}{{ unsupported "FULLTEXT and SPATIAL indices" }}{{ end -}}
+{{- if .Params }}{{ unsupported "index storage parameters" }}{{ end -}}
 CREATE{{ if .Unique }} UNIQUE{{- end }} INDEX{{ if .IfNotExists }} IF NOT EXISTS{{ end }}
-{{- if .Name }} `{{.Name}}` {{ else }} 'unq_{{.Table.Name}}_{{ join .Columns "_"}}'{{ end }} ON `{{.Table.Name}}`(
+{{- if .Name }} `{{.Name}}` {{ else }} '{{ .GetName }}'{{ end }} ON `{{.Table.Name}}`(
   {{- range $i, $col := .Columns -}}
   {{- if $i }}, {{ end -}}
-  '{{- $col -}}'
+  {{- if $col.Expr }}{{ $col.Expr }}{{ else }}'{{- $col.Name -}}'{{ end }}
+  {{- if $col.Length }}{{ unsupported "index prefix lengths" }}{{ end }}
+  {{- with $col.Collate }} COLLATE {{ . }}{{ end }}
+  {{- with $col.Order }} {{ . }}{{ end }}
   {{- end -}}
 )
+{{- with .Where }} WHERE {{ . }}{{ end }}
 {{ end }}
+
+{{ define "foreign" -}}
+CONSTRAINT `{{ .GetName }}` FOREIGN KEY (
+  {{- range $i, $col := .Columns }}{{ if $i }}, {{ end }}'{{ $col }}'{{ end -}}
+) REFERENCES `{{ .RefTable }}`(
+  {{- range $i, $col := .RefColumns }}{{ if $i }}, {{ end }}'{{ $col }}'{{ end -}}
+)
+{{- with .OnDelete }} ON DELETE {{ Action . }}{{ end }}
+{{- with .OnUpdate }} ON UPDATE {{ Action . }}{{ end }}
+{{- end }}
+
+{{ define "add_column" -}}
+ALTER TABLE `{{ .Table.Name }}` ADD COLUMN {{ template "column" . }}
+{{- with .Foreign }} CONSTRAINT `{{ .GetName }}` REFERENCES `{{ .RefTable }}`('{{ index .RefColumns 0 }}')
+{{- with .OnDelete }} ON DELETE {{ Action . }}{{ end }}
+{{- with .OnUpdate }} ON UPDATE {{ Action . }}{{ end }}
+{{- end }}
+{{- end }}
+
+{{ define "rename_column" -}}
+ALTER TABLE `{{ .Table.Name }}` RENAME COLUMN `{{ .OriginalName }}` TO `{{ .Name }}`
+{{- end }}
+
+{{ define "check" -}}
+{{ with .Name }}CONSTRAINT `{{ . }}` {{ end }}CHECK ({{ .Expr }})
+{{- end }}
+
+{{ define "create_view" -}}
+{{ if .Materialized }}{{ unsupported "materialized views" }}{{ end -}}
+CREATE VIEW `{{ .Name }}` AS {{ .Query }}
+{{- end }}
+
+{{ define "refresh_view" -}}
+{{ unsupported "materialized views" }}
+{{- end }}
+
+{{ define "drop_view" -}}
+{{ if .Materialized }}{{ unsupported "materialized views" }}{{ end -}}
+DROP VIEW {{- if .IfExists }} IF EXISTS{{ end }} `{{ .Name }}`
+{{- end }}
+
+{{ define "create_trigger" -}}
+CREATE TRIGGER `{{ .Name }}` {{ .Timing }} {{ .Event }} ON `{{ .Table }}` FOR EACH ROW
+BEGIN
+{{ .Body }}
+END
+{{- end }}
+
+{{ define "drop_trigger" -}}
+DROP TRIGGER `{{ .Name }}`
+{{- end }}
+
+{{ define "create_enum" -}}
+{{ unsupported "enum types" }}
+{{- end }}
+
+{{ define "add_enum_value" -}}
+{{ unsupported "enum types" }}
+{{- end }}
+
+{{ define "drop_enum" -}}
+{{ unsupported "enum types" }}
+{{- end }}
+
+{{ define "create_sequence" -}}
+{{ unsupported "sequences" }}
+{{- end }}
+
+{{ define "restart_sequence" -}}
+{{ unsupported "sequences" }}
+{{- end }}
+
+{{ define "drop_sequence" -}}
+{{ unsupported "sequences" }}
+{{- end }}
+
+{{ define "create_extension" -}}
+{{ unsupported "extensions" }}
+{{- end }}
+
+{{ define "drop_extension" -}}
+{{ unsupported "extensions" }}
+{{- end }}
+
+{{ define "create_type" -}}
+{{ unsupported "composite types" }}
+{{- end }}
+
+{{ define "drop_type" -}}
+{{ unsupported "composite types" }}
+{{- end }}
+
+{{ define "create_domain" -}}
+{{ unsupported "domains" }}
+{{- end }}
+
+{{ define "drop_domain" -}}
+{{ unsupported "domains" }}
+{{- end }}
+
+{{ define "comment_table" -}}
+{{ unsupported "comments on existing tables" }}
+{{- end }}
+
+{{ define "drop_column" -}}
+ALTER TABLE `{{ .Table.Name }}` DROP COLUMN `{{ .Name }}`
+{{- end }}
+
+{{ define "drop_index" -}}
+DROP INDEX `{{ .Name }}`
+{{- end }}
+
+{{ define "drop_tables" -}}
+DROP TABLE {{ range $i, $t := . }}{{ if $i }}, {{ end }}`{{ $t }}`{{ end }}
+{{- end }}
+
+{{ define "create_search" -}}
+{{ if .Table.WithoutRowid }}{{ unsupported "full-text searches on WITHOUT ROWID tables" }}{{ end -}}
+CREATE VIRTUAL TABLE `{{ .Name }}` USING fts5(
+{{- range .Columns }}`{{ . }}`, {{ end -}}
+content={{ Quote .Table.Name }})
+{{- end }}
+
+{{ define "search_trigger" -}}
+CREATE TRIGGER `{{ .Name }}` AFTER {{ .Event }} ON `{{ .Search.Table.Name }}` BEGIN
+{{- if .Old }}
+  INSERT INTO `{{ .Search.Name }}` (`{{ .Search.Name }}`, rowid{{ range .Search.Columns }}, `{{ . }}`{{ end }}) VALUES ('delete', old.rowid{{ range .Search.Columns }}, old.`{{ . }}`{{ end }});
+{{- end }}
+{{- if .New }}
+  INSERT INTO `{{ .Search.Name }}` (rowid{{ range .Search.Columns }}, `{{ . }}`{{ end }}) VALUES (new.rowid{{ range .Search.Columns }}, new.`{{ . }}`{{ end }});
+{{- end }}
+END
+{{- end }}
+
+{{ define "rebuild_search" -}}
+INSERT INTO `{{ .Name }}` (`{{ .Name }}`) VALUES ('rebuild')
+{{- end }}
+
+{{ define "drop_search_trigger" -}}
+DROP TRIGGER `{{ .Name }}`
+{{- end }}
+
+{{ define "drop_search" -}}
+DROP TABLE `{{ .Name }}`
+{{- end }}
+
+{{ define "create_partition" -}}
+{{ unsupported "partitioned tables" }}
+{{- end }}
+
+{{ define "attach_partition" -}}
+{{ unsupported "partitioned tables" }}
+{{- end }}
+
+{{ define "detach_partition" -}}
+{{ unsupported "partitioned tables" }}
+{{- end }}
+
+{{ define "drop_partition" -}}
+{{ unsupported "partitioned tables" }}
+{{- end }}
+
+{{ define "enable_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "force_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "no_force_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "disable_row_security" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "create_policy" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "drop_policy" -}}
+{{ unsupported "row level security policies" }}
+{{- end }}
+
+{{ define "grant" -}}
+{{ unsupported "grants" }}
+{{- end }}
+
+{{ define "revoke" -}}
+{{ unsupported "grants" }}
+{{- end }}
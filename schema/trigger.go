@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wyattis/zee/isql/driver"
+)
+
+type TriggerTiming string
+
+const (
+	TriggerBefore    TriggerTiming = "BEFORE"
+	TriggerAfter     TriggerTiming = "AFTER"
+	TriggerInsteadOf TriggerTiming = "INSTEAD OF"
+)
+
+type TriggerEvent string
+
+const (
+	TriggerInsert TriggerEvent = "INSERT"
+	TriggerUpdate TriggerEvent = "UPDATE"
+	TriggerDelete TriggerEvent = "DELETE"
+)
+
+type TriggerMutator func(t *Trigger)
+
+type TriggerDef struct {
+	Schema *SchemaDef
+	Name   string
+	Table  string
+	Timing TriggerTiming
+	Event  TriggerEvent
+	Bodies map[driver.Type]string
+}
+
+// Body returns the body of the trigger for the schema's driver. SQLite and MySQL bodies are a list of statements.
+// Postgres bodies are PL/pgSQL placed in a trigger function that returns NEW, or OLD for delete triggers, once the
+// body completes.
+func (t *TriggerDef) Body() (body string, err error) {
+	body, ok := t.Bodies[t.Schema.Driver]
+	if !ok {
+		return "", fmt.Errorf("trigger %s has no body for %s", t.Name, t.Schema.Driver)
+	}
+	body = strings.TrimSpace(body)
+	if !strings.HasSuffix(body, ";") {
+		body += ";"
+	}
+	return
+}
+
+// FunctionName is the name of the function executed by a Postgres trigger
+func (t *TriggerDef) FunctionName() string {
+	return t.Name + "_fn"
+}
+
+// ReturnRow is the row returned by the Postgres trigger function
+func (t *TriggerDef) ReturnRow() string {
+	if t.Event == TriggerDelete {
+		return "OLD"
+	}
+	return "NEW"
+}
+
+func (t *TriggerDef) Statements() (statements []string, err error) {
+	steps, err := t.steps()
+	for _, statement := range steps {
+		statements = append(statements, statement.Sql)
+	}
+	return
+}
+
+func (t *TriggerDef) steps() (statements []Statement, err error) {
+	tmp, err := t.Schema.loadTemplates()
+	if err != nil {
+		return
+	}
+	var sql string
+	if tmp.Lookup("create_trigger_function") != nil {
+		if sql, err = execTemplate(tmp, "create_trigger_function", t); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
+	}
+	if sql, err = execTemplate(tmp, "create_trigger", t); err != nil {
+		return
+	}
+	statements = append(statements, Statement{Sql: sql})
+	return
+}
+
+func (t *TriggerDef) dropStatements() (statements []string, err error) {
+	tmp, err := t.Schema.loadTemplates()
+	if err != nil {
+		return
+	}
+	var sql string
+	if sql, err = execTemplate(tmp, "drop_trigger", t); err != nil {
+		return
+	}
+	statements = append(statements, sql)
+	if tmp.Lookup("drop_trigger_function") != nil {
+		if sql, err = execTemplate(tmp, "drop_trigger_function", t); err != nil {
+			return
+		}
+		statements = append(statements, sql)
+	}
+	return
+}
+
+type Trigger struct {
+	triggerDef *TriggerDef
+}
+
+func (t *Trigger) Before(event TriggerEvent) *Trigger {
+	t.triggerDef.Timing = TriggerBefore
+	t.triggerDef.Event = event
+	return t
+}
+
+func (t *Trigger) After(event TriggerEvent) *Trigger {
+	t.triggerDef.Timing = TriggerAfter
+	t.triggerDef.Event = event
+	return t
+}
+
+// InsteadOf replaces the event on a view. MySQL doesn't support INSTEAD OF triggers.
+func (t *Trigger) InsteadOf(event TriggerEvent) *Trigger {
+	t.triggerDef.Timing = TriggerInsteadOf
+	t.triggerDef.Event = event
+	return t
+}
+
+// Body sets the body of the trigger for a driver. Triggers fail to render for drivers without a body.
+func (t *Trigger) Body(driverType driver.Type, body string) *Trigger {
+	t.triggerDef.Bodies[driverType] = body
+	return t
+}
@@ -29,8 +29,11 @@ var mysqlTypeMap = typeMap{
 	TypeDate:      "DATE",
 	TypeTime:      "TIME",
 	TypeTimestamp: "TIMESTAMP",
+	TypeDouble:    "DOUBLE",
 	TypeBit:       "BIT",
 	TypeBinary:    "BINARY",
+	TypeVarBinary: "VARBINARY",
+	TypeBlob:      "BLOB",
 }
 
 var sqliteTypeMap = typeMap{
@@ -50,10 +53,48 @@ var sqliteTypeMap = typeMap{
 	TypeSmallInt:  "INTEGER",
 	TypeMediumInt: "INTEGER",
 	TypeBigInt:    "INTEGER",
-	TypeDecimal:   "REAL",
-	TypeNumeric:   "REAL",
+	// NUMERIC affinity stores values as numbers so they compare and sort numerically. Values with more significant digits
+	// than a REAL holds are rounded.
+	TypeDecimal:   "DECIMAL",
+	TypeNumeric:   "NUMERIC",
 	TypeFloat:     "REAL",
 	TypeDouble:    "REAL",
 	TypeBinary:    "BLOB",
 	TypeVarBinary: "BLOB",
+	TypeBlob:      "BLOB",
+}
+
+// sqliteStrictTypeMap is used for STRICT tables, which only allow INT, INTEGER, REAL, TEXT, BLOB and ANY. ANY keeps
+// fixed point values exactly as they were inserted.
+var sqliteStrictTypeMap = func() typeMap {
+	res := sqliteTypeMap.copy()
+	res[TypeDecimal] = "ANY"
+	res[TypeNumeric] = "ANY"
+	return res
+}()
+
+var postgresTypeMap = typeMap{
+	TypeVarChar:   "VARCHAR",
+	TypeNVarChar:  "VARCHAR",
+	TypeText:      "TEXT",
+	TypeJson:      "JSONB",
+	TypeDateTime:  "TIMESTAMP",
+	TypeEnum:      "TEXT",
+	TypeBoolean:   "BOOLEAN",
+	TypeInteger:   "INTEGER",
+	TypeTinyInt:   "SMALLINT",
+	TypeSmallInt:  "SMALLINT",
+	TypeMediumInt: "INTEGER",
+	TypeBigInt:    "BIGINT",
+	TypeDecimal:   "DECIMAL",
+	TypeNumeric:   "NUMERIC",
+	TypeFloat:     "REAL",
+	TypeDouble:    "DOUBLE PRECISION",
+	TypeDate:      "DATE",
+	TypeTime:      "TIME",
+	TypeTimestamp: "TIMESTAMP",
+	TypeBit:       "BIT",
+	TypeBinary:    "BYTEA",
+	TypeVarBinary: "BYTEA",
+	TypeBlob:      "BYTEA",
 }
@@ -0,0 +1,57 @@
+package schema
+
+import "github.com/wyattis/zee/isql/driver"
+
+type ViewDef struct {
+	Schema       *SchemaDef
+	Name         string
+	Query        string
+	OrReplace    bool
+	Materialized bool
+	IfExists     bool
+}
+
+func (v *ViewDef) Statements() (statements []string, err error) {
+	steps, err := v.steps()
+	for _, statement := range steps {
+		statements = append(statements, statement.Sql)
+	}
+	return
+}
+
+func (v *ViewDef) steps() (statements []Statement, err error) {
+	tmp, err := v.Schema.loadTemplates()
+	if err != nil {
+		return
+	}
+	var sql string
+	// SQLite doesn't have CREATE OR REPLACE VIEW
+	if v.OrReplace && v.Schema.Driver == driver.TypeSqlite3 {
+		drop := &ViewDef{Schema: v.Schema, Name: v.Name, IfExists: true}
+		if sql, err = execTemplate(tmp, "drop_view", drop); err != nil {
+			return
+		}
+		statements = append(statements, Statement{Sql: sql})
+	}
+	if sql, err = execTemplate(tmp, "create_view", v); err != nil {
+		return
+	}
+	statements = append(statements, Statement{Sql: sql})
+	return
+}
+
+func (v *ViewDef) dropStatement() (string, error) {
+	tmp, err := v.Schema.loadTemplates()
+	if err != nil {
+		return "", err
+	}
+	return execTemplate(tmp, "drop_view", v)
+}
+
+func (v *ViewDef) refreshStatement() (string, error) {
+	tmp, err := v.Schema.loadTemplates()
+	if err != nil {
+		return "", err
+	}
+	return execTemplate(tmp, "refresh_view", v)
+}